@@ -0,0 +1,221 @@
+package analysis
+
+import (
+	"fmt"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"math"
+	"math/big"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// BootstrapConfidence is the level of the confidence intervals of the bootstrap
+const BootstrapConfidence = 0.95
+
+// Bootstrap holds the uncertainty of the results of a poll, estimated by resampling its judges
+type Bootstrap struct {
+	Iterations int     // amount of resampled polls
+	Seed       int64   // of the random number generator ; the same seed gives the same results
+	Confidence float64 // level of the confidence intervals, like 0.95
+	// Proposals in the order they were submitted
+	Proposals []ProposalBootstrap
+}
+
+// ProposalBootstrap is the uncertainty of the results of a proposal
+type ProposalBootstrap struct {
+	RankProbabilities []float64 // probability of each rank, from rank 1, from 0 to 1
+	WinProbability    float64   // probability of rank 1, shared or not, from 0 to 1
+	// Confidence interval of the majority grade, as indices of the grades
+	MajorityGradeLow  int
+	MajorityGradeHigh int
+}
+
+// Resampler draws a poll from the judges of a deliberated poll, with replacement, using the random number generator.
+// It is called by all the workers at once, each with its own random number generator.
+type Resampler func(random *rand.Rand) (*judgment.PollTally, error)
+
+// MakeBootstrap resamples the judges of the round and deliberates each resampled poll,
+// in order to tell how likely each proposal is to get each rank.
+// The resampler draws as many judges as the poll holds, see ResampleJudges and ResampleTallies,
+// and tallies them like the poll was tallied, since the uncertainty depends on the actual amount of judges.
+// Iterations are shared between the workers, and each iteration has its own random number generator,
+// seeded from the seed and the iteration, so that the results do not depend on the amount of workers.
+// The deliberator is used by all the workers at once.
+func MakeBootstrap(
+	round *Round,
+	resample Resampler,
+	deliberator judgment.DeliberatorInterface,
+	iterations int,
+	seed int64,
+	workers int,
+) (*Bootstrap, error) {
+	if iterations <= 0 {
+		return nil, fmt.Errorf("the amount of bootstrap iterations must be positive, got %d", iterations)
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+
+	amountOfProposals := len(round.Proposals)
+	amountOfGrades := len(round.Grades)
+
+	// Each worker counts on its own, and the counts are summed at the end
+	type counts struct {
+		ranks  [][]int // [proposal][rank-1]
+		grades [][]int // [proposal][majority grade]
+		err    error
+	}
+	workersCounts := make([]*counts, workers)
+	waitGroup := sync.WaitGroup{}
+	for worker := 0; worker < workers; worker++ {
+		workerCounts := &counts{
+			ranks:  make([][]int, amountOfProposals),
+			grades: make([][]int, amountOfProposals),
+		}
+		for proposalIndex := range round.Proposals {
+			workerCounts.ranks[proposalIndex] = make([]int, amountOfProposals)
+			workerCounts.grades[proposalIndex] = make([]int, amountOfGrades)
+		}
+		workersCounts[worker] = workerCounts
+
+		waitGroup.Add(1)
+		go func(worker int) {
+			defer waitGroup.Done()
+			for iteration := worker; iteration < iterations; iteration += workers {
+				random := rand.New(rand.NewSource(seed + int64(iteration)))
+				resampled, resampleErr := resample(random)
+				if nil != resampleErr {
+					workerCounts.err = resampleErr
+					return
+				}
+				result, deliberationErr := deliberator.Deliberate(resampled)
+				if nil != deliberationErr {
+					workerCounts.err = deliberationErr
+					return
+				}
+				for _, proposalResult := range result.Proposals {
+					workerCounts.ranks[proposalResult.Index][proposalResult.Rank-1]++
+					workerCounts.grades[proposalResult.Index][proposalResult.Analysis.MedianGrade]++
+				}
+			}
+		}(worker)
+	}
+	waitGroup.Wait()
+
+	bootstrap := &Bootstrap{
+		Iterations: iterations,
+		Seed:       seed,
+		Confidence: BootstrapConfidence,
+		Proposals:  make([]ProposalBootstrap, amountOfProposals),
+	}
+	for proposalIndex := range round.Proposals {
+		ranks := make([]int, amountOfProposals)
+		grades := make([]int, amountOfGrades)
+		for _, workerCounts := range workersCounts {
+			if nil != workerCounts.err {
+				return nil, fmt.Errorf("failed to deliberate a resampled poll: %s", workerCounts.err)
+			}
+			for rankIndex, amount := range workerCounts.ranks[proposalIndex] {
+				ranks[rankIndex] += amount
+			}
+			for gradeIndex, amount := range workerCounts.grades[proposalIndex] {
+				grades[gradeIndex] += amount
+			}
+		}
+
+		rankProbabilities := make([]float64, 0, amountOfProposals)
+		for _, amount := range ranks {
+			rankProbabilities = append(rankProbabilities, float64(amount)/float64(iterations))
+		}
+		low, high := computePercentileInterval(grades, iterations, BootstrapConfidence)
+		bootstrap.Proposals[proposalIndex] = ProposalBootstrap{
+			RankProbabilities: rankProbabilities,
+			WinProbability:    rankProbabilities[0],
+			MajorityGradeLow:  low,
+			MajorityGradeHigh: high,
+		}
+	}
+
+	return bootstrap, nil
+}
+
+// ResampleJudges draws as many judges as there are from the judges, with replacement, along with their weights.
+// The judgments are the grade indices given by each judge to each proposal, like the readers make them,
+// and the weights are those of each judge, or nil when the judges are not weighted.
+func ResampleJudges(judgments [][]int, weights []*big.Rat, random *rand.Rand) ([][]int, []*big.Rat) {
+	resampled := make([][]int, 0, len(judgments))
+	var resampledWeights []*big.Rat
+	if nil != weights {
+		resampledWeights = make([]*big.Rat, 0, len(judgments))
+	}
+	for range judgments {
+		judgeIndex := random.Intn(len(judgments))
+		resampled = append(resampled, judgments[judgeIndex])
+		if nil != weights {
+			resampledWeights = append(resampledWeights, weights[judgeIndex])
+		}
+	}
+	return resampled, resampledWeights
+}
+
+// ResampleTallies draws as many judgments as each proposal holds from its own tally, with replacement,
+// when the judges themselves are unknown.  The tallies must be neither normalized nor scaled,
+// so that they hold the actual amounts of judgments ; an amount like 2.5 is drawn as 3 judgments.
+func ResampleTallies(tallies [][]*big.Rat, random *rand.Rand) [][]*big.Rat {
+	resampled := make([][]*big.Rat, 0, len(tallies))
+	for _, proposalTally := range tallies {
+		cumulated := make([]float64, 0, len(proposalTally))
+		total := 0.0
+		for _, amount := range proposalTally {
+			amountAsFloat, _ := amount.Float64()
+			total += amountAsFloat
+			cumulated = append(cumulated, total)
+		}
+
+		tally := make([]int64, len(proposalTally))
+		amountOfDraws := int64(math.Round(total))
+		for draw := int64(0); draw < amountOfDraws; draw++ {
+			target := random.Float64() * total
+			gradeIndex := sort.Search(len(cumulated), func(i int) bool {
+				return cumulated[i] > target
+			})
+			if gradeIndex == len(cumulated) {
+				gradeIndex--
+			}
+			tally[gradeIndex]++
+		}
+
+		resampledTally := make([]*big.Rat, 0, len(tally))
+		for _, amount := range tally {
+			resampledTally = append(resampledTally, big.NewRat(amount, 1))
+		}
+		resampled = append(resampled, resampledTally)
+	}
+	return resampled
+}
+
+// computePercentileInterval returns the grades at the edges of the central interval holding the confidence level
+// of the observations, from the amounts of observations of each grade
+func computePercentileInterval(amounts []int, total int, confidence float64) (low int, high int) {
+	lowTarget := (1.0 - confidence) / 2.0 * float64(total)
+	highTarget := (1.0 + confidence) / 2.0 * float64(total)
+	low, high = -1, -1
+	cumulated := 0
+	for gradeIndex, amount := range amounts {
+		cumulated += amount
+		if -1 == low && float64(cumulated) > lowTarget {
+			low = gradeIndex
+		}
+		if -1 == high && float64(cumulated) >= highTarget {
+			high = gradeIndex
+		}
+	}
+	if -1 == high {
+		high = len(amounts) - 1
+	}
+	if -1 == low {
+		low = high
+	}
+	return
+}
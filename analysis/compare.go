@@ -0,0 +1,146 @@
+// Package analysis inspects deliberated polls, beyond their ranking.
+// It only depends on the judgment library, so that formatters and commands may all use it.
+package analysis
+
+import (
+	"fmt"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"strings"
+)
+
+// Round is a deliberated poll, like one round of a poll held regularly
+type Round struct {
+	Tally     *judgment.PollTally
+	Result    *judgment.PollResult
+	Proposals []string // in the order they were submitted
+	Grades    []string // from "worst" to "best"
+	Scale     float64  // the tallies were multiplied by it, so that they are integers
+}
+
+// Status of a proposal between two rounds
+const (
+	StatusKept    = "kept"
+	StatusNew     = "new"
+	StatusRemoved = "removed"
+)
+
+// Comparison is what moved between two rounds of a poll
+type Comparison struct {
+	Grades               []string // from "worst" to "best", as in the later round
+	AmountOfJudgesBefore float64
+	AmountOfJudgesAfter  float64
+	// Proposals in the order of their rank in the later round, followed by the removed ones
+	Proposals []ProposalComparison
+}
+
+// ProposalComparison is what moved for a proposal between two rounds.
+// Fields about a round the proposal is absent from are left empty.
+type ProposalComparison struct {
+	Name                string
+	Status              string // one of kept, new, removed
+	RankBefore          int    // from 1, or 0 when the proposal is new
+	RankAfter           int    // from 1, or 0 when the proposal was removed
+	RankChange          int    // positive when the proposal climbed in the ranking
+	MajorityGradeBefore int    // index of the majority grade, or -1 when the proposal is new
+	MajorityGradeAfter  int    // index of the majority grade, or -1 when the proposal was removed
+	MajorityGradeChange int    // in grades, positive when the majority grade got better
+	TallyBefore         []float64
+	TallyAfter          []float64
+	TallyDelta          []float64 // after minus before, per grade
+}
+
+// Compare two rounds of a poll, matching their proposals by name.
+// Both rounds must use the same amount of grades.
+func Compare(before *Round, after *Round) (*Comparison, error) {
+	if len(before.Grades) != len(after.Grades) {
+		return nil, fmt.Errorf(
+			"the polls do not use the same grades: found %d grades before and %d after",
+			len(before.Grades), len(after.Grades),
+		)
+	}
+
+	comparison := &Comparison{
+		Grades:               after.Grades,
+		AmountOfJudgesBefore: float64(before.Tally.AmountOfJudges) / before.Scale,
+		AmountOfJudgesAfter:  float64(after.Tally.AmountOfJudges) / after.Scale,
+		Proposals:            make([]ProposalComparison, 0, len(after.Proposals)),
+	}
+
+	beforeIndices := indexProposalsByName(before.Proposals)
+	afterIndices := indexProposalsByName(after.Proposals)
+
+	for _, afterResult := range after.Result.ProposalsSorted {
+		name := after.Proposals[afterResult.Index]
+		if afterIndices[strings.TrimSpace(name)] != afterResult.Index {
+			continue // duplicate name, we only compare the first one
+		}
+		proposalComparison := ProposalComparison{
+			Name:                name,
+			Status:              StatusNew,
+			RankAfter:           afterResult.Rank,
+			MajorityGradeBefore: -1,
+			MajorityGradeAfter:  int(afterResult.Analysis.MedianGrade),
+			TallyAfter:          readTally(after, afterResult.Index),
+		}
+		beforeIndex, wasThere := beforeIndices[strings.TrimSpace(name)]
+		if wasThere {
+			beforeResult := before.Result.Proposals[beforeIndex]
+			proposalComparison.Status = StatusKept
+			proposalComparison.RankBefore = beforeResult.Rank
+			proposalComparison.RankChange = beforeResult.Rank - afterResult.Rank
+			proposalComparison.MajorityGradeBefore = int(beforeResult.Analysis.MedianGrade)
+			proposalComparison.MajorityGradeChange = proposalComparison.MajorityGradeAfter -
+				proposalComparison.MajorityGradeBefore
+			proposalComparison.TallyBefore = readTally(before, beforeIndex)
+			proposalComparison.TallyDelta = make([]float64, 0, len(after.Grades))
+			for gradeIndex := range after.Grades {
+				proposalComparison.TallyDelta = append(
+					proposalComparison.TallyDelta,
+					proposalComparison.TallyAfter[gradeIndex]-proposalComparison.TallyBefore[gradeIndex],
+				)
+			}
+		}
+		comparison.Proposals = append(comparison.Proposals, proposalComparison)
+	}
+
+	for _, beforeResult := range before.Result.ProposalsSorted {
+		name := before.Proposals[beforeResult.Index]
+		if beforeIndices[strings.TrimSpace(name)] != beforeResult.Index {
+			continue
+		}
+		if _, isStillThere := afterIndices[strings.TrimSpace(name)]; isStillThere {
+			continue
+		}
+		comparison.Proposals = append(comparison.Proposals, ProposalComparison{
+			Name:                name,
+			Status:              StatusRemoved,
+			RankBefore:          beforeResult.Rank,
+			MajorityGradeBefore: int(beforeResult.Analysis.MedianGrade),
+			MajorityGradeAfter:  -1,
+			TallyBefore:         readTally(before, beforeResult.Index),
+		})
+	}
+
+	return comparison, nil
+}
+
+// indexProposalsByName maps the names of the proposals to their first index
+func indexProposalsByName(proposals []string) map[string]int {
+	indices := make(map[string]int, len(proposals))
+	for proposalIndex, proposal := range proposals {
+		name := strings.TrimSpace(proposal)
+		if _, alreadyThere := indices[name]; !alreadyThere {
+			indices[name] = proposalIndex
+		}
+	}
+	return indices
+}
+
+// readTally returns the unscaled tally of the proposal
+func readTally(round *Round, proposalIndex int) []float64 {
+	tally := make([]float64, 0, len(round.Grades))
+	for _, amount := range round.Tally.Proposals[proposalIndex].Tally {
+		tally = append(tally, float64(amount)/round.Scale)
+	}
+	return tally
+}
@@ -0,0 +1,113 @@
+package analysis
+
+import (
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"reflect"
+	"testing"
+)
+
+// makeRound deliberates the tallies with Majority Judgment, for the tests.
+// The tallies must be balanced, and are not scaled.  There may be up to six grades.
+func makeRound(t *testing.T, proposals []string, tallies [][]uint64) *Round {
+	t.Helper()
+	pollTally := &judgment.PollTally{
+		Proposals: make([]*judgment.ProposalTally, 0, len(tallies)),
+	}
+	for _, tally := range tallies {
+		pollTally.Proposals = append(pollTally.Proposals, &judgment.ProposalTally{Tally: tally})
+	}
+	pollTally.GuessAmountOfJudges()
+	result, deliberationErr := (&judgment.MajorityJudgment{}).Deliberate(pollTally)
+	if nil != deliberationErr {
+		t.Fatal(deliberationErr)
+	}
+
+	return &Round{
+		Tally:     pollTally,
+		Result:    result,
+		Proposals: proposals,
+		Grades:    []string{"reject", "poor", "fair", "good", "very good", "excellent"}[:len(tallies[0])],
+		Scale:     1.0,
+	}
+}
+
+func TestCompare(t *testing.T) {
+	before := makeRound(t, []string{"Pizza", "Chips", "Pasta"}, [][]uint64{
+		{1, 2, 3},
+		{3, 2, 1},
+		{2, 2, 2},
+	})
+	after := makeRound(t, []string{"Chips", "Pizza", "Salad"}, [][]uint64{
+		{0, 1, 5},
+		{3, 2, 1},
+		{2, 3, 1},
+	})
+
+	comparison, compareErr := Compare(before, after)
+	if nil != compareErr {
+		t.Fatal(compareErr)
+	}
+
+	expected := []ProposalComparison{
+		{
+			Name:                "Chips",
+			Status:              StatusKept,
+			RankBefore:          3,
+			RankAfter:           1,
+			RankChange:          2,
+			MajorityGradeBefore: 0,
+			MajorityGradeAfter:  2,
+			MajorityGradeChange: 2,
+			TallyBefore:         []float64{3, 2, 1},
+			TallyAfter:          []float64{0, 1, 5},
+			TallyDelta:          []float64{-3, -1, 4},
+		},
+		{
+			Name:                "Salad",
+			Status:              StatusNew,
+			RankAfter:           2,
+			MajorityGradeBefore: -1,
+			MajorityGradeAfter:  1,
+			TallyAfter:          []float64{2, 3, 1},
+		},
+		{
+			Name:                "Pizza",
+			Status:              StatusKept,
+			RankBefore:          1,
+			RankAfter:           3,
+			RankChange:          -2,
+			MajorityGradeBefore: 1,
+			MajorityGradeAfter:  0,
+			MajorityGradeChange: -1,
+			TallyBefore:         []float64{1, 2, 3},
+			TallyAfter:          []float64{3, 2, 1},
+			TallyDelta:          []float64{2, 0, -2},
+		},
+		{
+			Name:                "Pasta",
+			Status:              StatusRemoved,
+			RankBefore:          2,
+			MajorityGradeBefore: 1,
+			MajorityGradeAfter:  -1,
+			TallyBefore:         []float64{2, 2, 2},
+		},
+	}
+	if !reflect.DeepEqual(expected, comparison.Proposals) {
+		t.Errorf("expected %+v, got %+v", expected, comparison.Proposals)
+	}
+	if 6 != comparison.AmountOfJudgesBefore || 6 != comparison.AmountOfJudgesAfter {
+		t.Errorf(
+			"expected 6 judges before and after, got %v and %v",
+			comparison.AmountOfJudgesBefore, comparison.AmountOfJudgesAfter,
+		)
+	}
+}
+
+func TestCompareDifferentGrades(t *testing.T) {
+	before := makeRound(t, []string{"Pizza"}, [][]uint64{{1, 2, 3}})
+	after := makeRound(t, []string{"Pizza"}, [][]uint64{{1, 2, 3, 4}})
+
+	if _, compareErr := Compare(before, after); nil == compareErr {
+		t.Error("expected polls with different grades not to be compared")
+	}
+}
@@ -0,0 +1,20 @@
+package analysis
+
+import "github.com/mieuxvoter/majority-judgment-library-go/judgment"
+
+// Deliberation holds what was computed along with the ranking of a poll, for the formatters to show it.
+// Each of them is nil unless it was asked for.
+type Deliberation struct {
+	// Tally of the judgments counted once each, regardless of the weights, only when the judges were weighted
+	UnweightedTally *judgment.PollTally
+	// Results of other voting methods, shown next to the ranks
+	Methods *MethodsComparison
+	// How many judgments would have to change to swap proposals
+	Margins *Margins
+	// Probabilities of the ranks and intervals of the majority grades, from resampled polls
+	Bootstrap *Bootstrap
+	// Results of each group of judges, deliberated apart
+	Groups *Groups
+	// Proposals elected from the top of the ranking
+	Winners *Winners
+}
@@ -0,0 +1,78 @@
+package analysis
+
+// Duels holds the outcome of the majority judgment comparison of every pair of proposals
+type Duels struct {
+	Grades    []string          // from "worst" to "best"
+	Proposals []DuelingProposal // in the order they were submitted
+	Duels     [][]Duel          // Duels[i][j] is proposal i against proposal j, in the order of Proposals
+}
+
+// DuelingProposal is a proposal, as seen by Duels
+type DuelingProposal struct {
+	Index int // in the input order, from 0
+	Name  string
+	Rank  int // from 1
+}
+
+// Duel is the outcome of the comparison of two proposals
+type Duel struct {
+	Winner        int    // index of the winning proposal, or -1 on a tie and against itself
+	DecidedBy     string // one of majority-grade, second-group, tie, or empty against itself
+	DecidingGrade int    // majority grade at the deciding step, of the winner, or their shared one on a tie
+	Step          int    // step of the tie-breaking that decided, from 1, or 0 against itself
+}
+
+// IsAgainstItself tells whether the duel is on the diagonal of the matrix
+func (d *Duel) IsAgainstItself() bool {
+	return "" == d.DecidedBy
+}
+
+// MakeDuels compares every pair of proposals of the round, as the Majority Judgment does.
+// The winner of a duel is always the proposal ranked above the other,
+// but the duels also tell at which grade and tie-breaking step each pair was decided.
+func MakeDuels(round *Round) (*Duels, error) {
+	amountOfProposals := len(round.Proposals)
+	duels := &Duels{
+		Grades:    round.Grades,
+		Proposals: make([]DuelingProposal, 0, amountOfProposals),
+		Duels:     make([][]Duel, amountOfProposals),
+	}
+
+	for _, proposalResult := range round.Result.Proposals {
+		duels.Proposals = append(duels.Proposals, DuelingProposal{
+			Index: proposalResult.Index,
+			Name:  round.Proposals[proposalResult.Index],
+			Rank:  proposalResult.Rank,
+		})
+	}
+
+	for i := range duels.Duels {
+		duels.Duels[i] = make([]Duel, amountOfProposals)
+		duels.Duels[i][i] = Duel{Winner: -1, DecidingGrade: -1}
+	}
+	for i := 0; i < amountOfProposals; i++ {
+		for j := i + 1; j < amountOfProposals; j++ {
+			steps, decidedBy, winner, replayErr := replayTieBreaking(round, i, j)
+			if nil != replayErr {
+				return nil, replayErr
+			}
+			decisiveStep := steps[len(steps)-1]
+			decidingGrade := decisiveStep.First.MajorityGrade
+			if j == winner {
+				decidingGrade = decisiveStep.Second.MajorityGrade
+			} else if -1 == winner {
+				decidingGrade = steps[0].First.MajorityGrade
+			}
+			duel := Duel{
+				Winner:        winner,
+				DecidedBy:     decidedBy,
+				DecidingGrade: decidingGrade,
+				Step:          len(steps),
+			}
+			duels.Duels[i][j] = duel
+			duels.Duels[j][i] = duel
+		}
+	}
+
+	return duels, nil
+}
@@ -0,0 +1,176 @@
+package analysis
+
+import (
+	"fmt"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+)
+
+// What decided the order of two proposals ranked next to each other
+const (
+	DecidedByMajorityGrade = "majority-grade"
+	DecidedBySecondGroup   = "second-group"
+	DecidedByNothing       = "tie" // the proposals are perfectly tied and share their rank
+)
+
+// Explanation justifies the ranking of a poll, one pair of proposals ranked next to each other at a time
+type Explanation struct {
+	Grades         []string // from "worst" to "best"
+	AmountOfJudges float64
+	Decisions      []Decision // from the top of the ranking to its bottom
+}
+
+// Decision explains why a proposal is ranked above the next one in the ranking
+type Decision struct {
+	Above     ExplainedProposal
+	Below     ExplainedProposal
+	DecidedBy string // one of majority-grade, second-group, tie
+	// Steps of the tie-breaking, the last one being decisive unless the proposals are tied.
+	// The first step is made on the tallies themselves, and each following step
+	// on the tallies whose majority grade judgments were moved into the grade of their second group.
+	Steps []TieBreakingStep
+}
+
+// ExplainedProposal is a proposal, as seen by a Decision
+type ExplainedProposal struct {
+	Index         int // in the input order, from 0
+	Name          string
+	Rank          int // from 1
+	MajorityGrade int // index of the grade, from 0 for the "worst" grade
+}
+
+// TieBreakingStep compares the (regraded) tallies of two proposals, in the order they were compared.
+// In a Decision, the first proposal is the one ranked above ; in a duel, it may as well be the loser.
+type TieBreakingStep struct {
+	First  StepAnalysis
+	Second StepAnalysis
+}
+
+// StepAnalysis is the analysis of a (regraded) tally of a proposal, during a step of the tie-breaking
+type StepAnalysis struct {
+	MajorityGrade      int     // index of the grade
+	SecondGroupGrade   int     // index of the grade of the biggest group outside the majority grade
+	SecondGroupSize    float64 // in judgments, unscaled
+	SecondGroupSign    int     // +1 for an adhesion group (above), -1 for a contestation group (below), 0 for none
+	SecondGroupPercent float64 // of the judgments of the proposal, from 0 to 100
+}
+
+// Explain why each proposal of the round is ranked above the next one,
+// by replaying the tie-breaking of the Majority Judgment on each pair of proposals ranked next to each other.
+func Explain(round *Round) (*Explanation, error) {
+	explanation := &Explanation{
+		Grades:         round.Grades,
+		AmountOfJudges: float64(round.Tally.AmountOfJudges) / round.Scale,
+		Decisions:      make([]Decision, 0, len(round.Result.ProposalsSorted)),
+	}
+
+	sorted := round.Result.ProposalsSorted
+	for i := 0; i+1 < len(sorted); i++ {
+		decision, decisionErr := explainDecision(round, sorted[i], sorted[i+1])
+		if nil != decisionErr {
+			return nil, decisionErr
+		}
+		explanation.Decisions = append(explanation.Decisions, *decision)
+	}
+
+	return explanation, nil
+}
+
+// explainDecision replays the tie-breaking of two proposals ranked next to each other
+func explainDecision(round *Round, above *judgment.ProposalResult, below *judgment.ProposalResult) (*Decision, error) {
+	steps, decidedBy, _, replayErr := replayTieBreaking(round, above.Index, below.Index)
+	if nil != replayErr {
+		return nil, replayErr
+	}
+
+	return &Decision{
+		Above:     explainProposal(round, above),
+		Below:     explainProposal(round, below),
+		DecidedBy: decidedBy,
+		Steps:     steps,
+	}, nil
+}
+
+// replayTieBreaking compares two proposals like the judgment library computes their scores, step by step.
+// The winner is the index of the proposal ranked above the other, or -1 when they are tied.
+func replayTieBreaking(round *Round, first int, second int) (
+	steps []TieBreakingStep,
+	decidedBy string,
+	winner int,
+	err error,
+) {
+	firstTally := round.Tally.Proposals[first].Copy()
+	secondTally := round.Tally.Proposals[second].Copy()
+	firstAnalysis := &judgment.ProposalAnalysis{}
+	secondAnalysis := &judgment.ProposalAnalysis{}
+	steps = make([]TieBreakingStep, 0, 1)
+	for range round.Grades {
+		firstAnalysis.Run(firstTally, true)
+		secondAnalysis.Run(secondTally, true)
+		steps = append(steps, TieBreakingStep{
+			First:  makeStepAnalysis(firstAnalysis, round.Scale),
+			Second: makeStepAnalysis(secondAnalysis, round.Scale),
+		})
+
+		if firstAnalysis.MedianGrade != secondAnalysis.MedianGrade {
+			if firstAnalysis.MedianGrade > secondAnalysis.MedianGrade {
+				return steps, DecidedByMajorityGrade, first, nil
+			}
+			return steps, DecidedByMajorityGrade, second, nil
+		}
+		firstScore := computeAdhesionScore(firstAnalysis)
+		secondScore := computeAdhesionScore(secondAnalysis)
+		if firstScore != secondScore {
+			if firstScore > secondScore {
+				return steps, DecidedBySecondGroup, first, nil
+			}
+			return steps, DecidedBySecondGroup, second, nil
+		}
+
+		for _, regrading := range []struct {
+			tally    *judgment.ProposalTally
+			analysis *judgment.ProposalAnalysis
+		}{{firstTally, firstAnalysis}, {secondTally, secondAnalysis}} {
+			regradingErr := regrading.tally.RegradeJudgments(
+				regrading.analysis.MedianGrade,
+				regrading.analysis.SecondMedianGrade,
+			)
+			if nil != regradingErr {
+				return nil, "", -1, fmt.Errorf("failed to replay the tie-breaking: %s", regradingErr)
+			}
+		}
+	}
+
+	return steps, DecidedByNothing, -1, nil
+}
+
+// explainProposal describes the proposal of the result
+func explainProposal(round *Round, proposalResult *judgment.ProposalResult) ExplainedProposal {
+	return ExplainedProposal{
+		Index:         proposalResult.Index,
+		Name:          round.Proposals[proposalResult.Index],
+		Rank:          proposalResult.Rank,
+		MajorityGrade: int(proposalResult.Analysis.MedianGrade),
+	}
+}
+
+// makeStepAnalysis copies what matters of the analysis, unscaled
+func makeStepAnalysis(proposalAnalysis *judgment.ProposalAnalysis, scale float64) StepAnalysis {
+	percent := 0.0
+	if proposalAnalysis.TotalSize > 0 {
+		percent = 100.0 * float64(proposalAnalysis.SecondGroupSize) / float64(proposalAnalysis.TotalSize)
+	}
+	return StepAnalysis{
+		MajorityGrade:      int(proposalAnalysis.MedianGrade),
+		SecondGroupGrade:   int(proposalAnalysis.SecondMedianGrade),
+		SecondGroupSize:    float64(proposalAnalysis.SecondGroupSize) / scale,
+		SecondGroupSign:    proposalAnalysis.SecondGroupSign,
+		SecondGroupPercent: percent,
+	}
+}
+
+// computeAdhesionScore is what the judgment library compares once the majority grades are equal.
+// A proposal with an adhesion group beats one with a contestation group,
+// a bigger adhesion group beats a smaller one, and a smaller contestation group beats a bigger one.
+func computeAdhesionScore(proposalAnalysis *judgment.ProposalAnalysis) int64 {
+	return int64(proposalAnalysis.TotalSize) + int64(proposalAnalysis.SecondGroupSign)*int64(proposalAnalysis.SecondGroupSize)
+}
@@ -0,0 +1,155 @@
+package analysis
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	round := makeRound(t, []string{"Pizza", "Chips", "Pasta"}, [][]uint64{
+		{3, 2, 1, 4, 4, 2},
+		{2, 3, 0, 4, 3, 4},
+		{4, 5, 1, 4, 0, 2},
+	})
+
+	explanation, explainErr := Explain(round)
+	if nil != explainErr {
+		t.Fatal(explainErr)
+	}
+
+	pizza := ExplainedProposal{Index: 0, Name: "Pizza", Rank: 2, MajorityGrade: 3}
+	chips := ExplainedProposal{Index: 1, Name: "Chips", Rank: 1, MajorityGrade: 3}
+	pasta := ExplainedProposal{Index: 2, Name: "Pasta", Rank: 3, MajorityGrade: 1}
+	pizzaStep := StepAnalysis{
+		MajorityGrade:      3,
+		SecondGroupGrade:   2,
+		SecondGroupSize:    6,
+		SecondGroupSign:    -1,
+		SecondGroupPercent: 37.5,
+	}
+	expected := []Decision{
+		{
+			Above:     chips,
+			Below:     pizza,
+			DecidedBy: DecidedBySecondGroup,
+			Steps: []TieBreakingStep{{
+				First: StepAnalysis{
+					MajorityGrade:      3,
+					SecondGroupGrade:   4,
+					SecondGroupSize:    7,
+					SecondGroupSign:    1,
+					SecondGroupPercent: 43.75,
+				},
+				Second: pizzaStep,
+			}},
+		},
+		{
+			Above:     pizza,
+			Below:     pasta,
+			DecidedBy: DecidedByMajorityGrade,
+			Steps: []TieBreakingStep{{
+				First: pizzaStep,
+				Second: StepAnalysis{
+					MajorityGrade:      1,
+					SecondGroupGrade:   2,
+					SecondGroupSize:    7,
+					SecondGroupSign:    1,
+					SecondGroupPercent: 43.75,
+				},
+			}},
+		},
+	}
+	if !reflect.DeepEqual(expected, explanation.Decisions) {
+		t.Errorf("expected %+v, got %+v", expected, explanation.Decisions)
+	}
+	if 16 != explanation.AmountOfJudges {
+		t.Errorf("expected 16 judges, got %v", explanation.AmountOfJudges)
+	}
+}
+
+func TestExplainPerfectTie(t *testing.T) {
+	round := makeRound(t, []string{"Tea", "Coffee"}, [][]uint64{
+		{1, 2, 1},
+		{1, 2, 1},
+	})
+
+	explanation, explainErr := Explain(round)
+	if nil != explainErr {
+		t.Fatal(explainErr)
+	}
+
+	decision := explanation.Decisions[0]
+	if DecidedByNothing != decision.DecidedBy {
+		t.Errorf("expected the proposals to be tied, got %s", decision.DecidedBy)
+	}
+	if decision.Above.Rank != decision.Below.Rank {
+		t.Errorf("expected tied proposals to share their rank, got %d and %d", decision.Above.Rank, decision.Below.Rank)
+	}
+}
+
+func TestExplainTieBrokenAtALaterMedian(t *testing.T) {
+	// Both have the majority grade good, and one judgment below it: they are tied until the second step
+	round := makeRound(t, []string{"Chips", "Pizza"}, [][]uint64{
+		{1, 0, 3},
+		{0, 1, 3},
+	})
+
+	explanation, explainErr := Explain(round)
+	if nil != explainErr {
+		t.Fatal(explainErr)
+	}
+
+	decision := explanation.Decisions[0]
+	if "Pizza" != decision.Above.Name || 1 != decision.Above.Rank || 2 != decision.Below.Rank {
+		t.Errorf("expected Pizza #1 above Chips #2, like the library ranks them, got %+v", decision)
+	}
+	if DecidedByMajorityGrade != decision.DecidedBy || 2 != len(decision.Steps) {
+		t.Fatalf("expected the majority grades to decide at the second step, got %+v", decision)
+	}
+	first, second := decision.Steps[0], decision.Steps[1]
+	if first.First.MajorityGrade != first.Second.MajorityGrade ||
+		first.First.SecondGroupSize != first.Second.SecondGroupSize ||
+		first.First.SecondGroupSign != first.Second.SecondGroupSign {
+		t.Errorf("expected the first step to be tied, got %+v", first)
+	}
+	if 1 != second.First.MajorityGrade || 0 != second.Second.MajorityGrade {
+		t.Errorf("expected the majority grades poor and reject at the second step, got %+v", second)
+	}
+}
+
+func TestReplayAgreesWithTheLibrary(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	for poll := 0; poll < 500; poll++ {
+		// Few judges and grades, so that ties broken at later medians are frequent
+		tallies := make([][]uint64, 0, 4)
+		for proposalIndex := 0; proposalIndex < 4; proposalIndex++ {
+			tally := make([]uint64, 4)
+			for judge := 0; judge < 6; judge++ {
+				tally[random.Intn(len(tally))]++
+			}
+			tallies = append(tallies, tally)
+		}
+		round := makeRound(t, []string{"A", "B", "C", "D"}, tallies)
+
+		for first := range tallies {
+			for second := first + 1; second < len(tallies); second++ {
+				_, _, winner, replayErr := replayTieBreaking(round, first, second)
+				if nil != replayErr {
+					t.Fatal(replayErr)
+				}
+				firstRank := round.Result.Proposals[first].Rank
+				secondRank := round.Result.Proposals[second].Rank
+				agrees := (-1 == winner && firstRank == secondRank) ||
+					(first == winner && firstRank < secondRank) ||
+					(second == winner && secondRank < firstRank)
+				if !agrees {
+					t.Fatalf(
+						"the replay of %v against %v elects %d, but the library ranks them #%d and #%d",
+						tallies[first], tallies[second], winner, firstRank, secondRank,
+					)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package analysis
+
+// Groups are the results of the judges split by a column of the ballots, like their department,
+// each group being deliberated apart from the others
+type Groups struct {
+	Column string  // name of the column the judges were grouped by
+	Groups []Group // in the order of their names
+	// Least difference between the highest and the lowest rank of a proposal among the groups
+	// for that proposal to be Divergent
+	DivergenceThreshold int
+	// Proposals in the order they were submitted
+	Proposals []ProposalGroups
+}
+
+// Group is a group of judges and the results of their judgments alone
+type Group struct {
+	Name           string // value of the column
+	AmountOfJudges int    // amount of ballots in the group, regardless of their weights
+	Round          *Round
+}
+
+// ProposalGroups is how the rank of a proposal differs between the groups
+type ProposalGroups struct {
+	HighestRank int  // best rank among the groups, like 1
+	LowestRank  int  // worst rank among the groups
+	Divergent   bool // whether the ranks differ by the DivergenceThreshold or more
+}
+
+// MakeGroups gathers the results of each group, and finds the proposals whose rank differs strongly between them.
+// Ranks differ strongly when they are apart by half the amount of proposals or more,
+// like a proposal ranked first by a group and in the lower half of the ranking by another.
+func MakeGroups(column string, groups []Group, amountOfProposals int) *Groups {
+	threshold := (amountOfProposals + 1) / 2
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	proposals := make([]ProposalGroups, 0, amountOfProposals)
+	for proposalIndex := 0; proposalIndex < amountOfProposals; proposalIndex++ {
+		proposalGroups := ProposalGroups{}
+		for i, group := range groups {
+			rank := group.Round.Result.Proposals[proposalIndex].Rank
+			if 0 == i || rank < proposalGroups.HighestRank {
+				proposalGroups.HighestRank = rank
+			}
+			if 0 == i || rank > proposalGroups.LowestRank {
+				proposalGroups.LowestRank = rank
+			}
+		}
+		proposalGroups.Divergent = len(groups) > 1 &&
+			proposalGroups.LowestRank-proposalGroups.HighestRank >= threshold
+		proposals = append(proposals, proposalGroups)
+	}
+
+	return &Groups{
+		Column:              column,
+		Groups:              groups,
+		DivergenceThreshold: threshold,
+		Proposals:           proposals,
+	}
+}
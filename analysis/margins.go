@@ -0,0 +1,312 @@
+package analysis
+
+import (
+	"fmt"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"math"
+)
+
+// Above this amount of judgments per proposal, the margins would take too long to compute
+const maximumAmountOfJudgmentsForMargins = 100000
+
+// Margins tell how robust a ranking is, by how many judgments would have to change to swap proposals
+type Margins struct {
+	// The winner against each other proposal, in the order of the ranking
+	Winner []Margin
+	// Each proposal against the next one in the ranking, from the top of the ranking
+	Adjacent []Margin
+}
+
+// Margin is the least amount of changes of judgments that would rank a proposal strictly above another one.
+// Changes may lower the judgments of the proposal above, or raise the judgments of the proposal below.
+// Amounts are in judgments, unscaled, or -1 when no change could swap the proposals, like with a single grade.
+type Margin struct {
+	Above         int     // index of the proposal ranked above (or tied), in the input order
+	Below         int     // index of the proposal ranked below (or tied), in the input order
+	OneGradeMoves float64 // changes of a judgment by one grade, a judgment changed by two grades counting twice
+	AnyGradeMoves float64 // judgments changed to any other grade
+}
+
+// MakeMargins computes the margins of the winner against every other proposal,
+// and of every proposal against the next one in the ranking.
+// Margins are computed with the ordering of the Majority Judgment, which compares the judgments of two proposals
+// sorted then read from the (low) median outwards, as the tie-breaking of the Majority Judgment does.
+func MakeMargins(round *Round) (*Margins, error) {
+	margins := &Margins{
+		Winner:   make([]Margin, 0, len(round.Proposals)),
+		Adjacent: make([]Margin, 0, len(round.Proposals)),
+	}
+
+	sorted := round.Result.ProposalsSorted
+	for i := 1; i < len(sorted); i++ {
+		margin, marginErr := computeMargin(round, sorted[0].Index, sorted[i].Index)
+		if nil != marginErr {
+			return nil, marginErr
+		}
+		margins.Winner = append(margins.Winner, *margin)
+	}
+	for i := 0; i+1 < len(sorted); i++ {
+		margin, marginErr := computeMargin(round, sorted[i].Index, sorted[i+1].Index)
+		if nil != marginErr {
+			return nil, marginErr
+		}
+		margins.Adjacent = append(margins.Adjacent, *margin)
+	}
+
+	return margins, nil
+}
+
+// computeMargin computes the margin of the proposal above against the proposal below
+func computeMargin(round *Round, above int, below int) (*Margin, error) {
+	aboveTally := round.Tally.Proposals[above]
+	belowTally := round.Tally.Proposals[below]
+	amountOfGrades := len(aboveTally.Tally)
+
+	// Judgments are counted in units small enough to hold all the (scaled) amounts
+	unit := uint64(math.Max(1, math.Round(round.Scale)))
+	for _, proposalTally := range []*judgment.ProposalTally{aboveTally, belowTally} {
+		for _, amount := range proposalTally.Tally {
+			unit = computeGreatestCommonDivisor(unit, amount)
+		}
+	}
+	if aboveTally.CountJudgments() != belowTally.CountJudgments() {
+		return nil, fmt.Errorf("the proposals must have the same amount of judgments to compute their margins")
+	}
+	if aboveTally.CountJudgments()/unit > maximumAmountOfJudgmentsForMargins {
+		return nil, fmt.Errorf(
+			"too many judgments to compute the margins, the maximum is %d",
+			maximumAmountOfJudgmentsForMargins,
+		)
+	}
+
+	aboveGrades := expandTally(aboveTally, unit)
+	belowGrades := expandTally(belowTally, unit)
+	order := makeMedianOrder(len(aboveGrades))
+
+	oneGradeMoves := computeOneGradeMoves(aboveGrades, belowGrades, order, amountOfGrades)
+	anyGradeMoves := computeAnyGradeMoves(aboveGrades, belowGrades, order, amountOfGrades)
+
+	toJudgments := func(moves int) float64 {
+		if moves < 0 {
+			return -1
+		}
+		return float64(moves) * float64(unit) / round.Scale
+	}
+
+	return &Margin{
+		Above:         above,
+		Below:         below,
+		OneGradeMoves: toJudgments(oneGradeMoves),
+		AnyGradeMoves: toJudgments(anyGradeMoves),
+	}, nil
+}
+
+// expandTally lists the grades of the judgments of the tally, from the "worst", one per unit
+func expandTally(proposalTally *judgment.ProposalTally, unit uint64) []int {
+	grades := make([]int, 0, proposalTally.CountJudgments()/unit)
+	for gradeIndex, amount := range proposalTally.Tally {
+		for i := uint64(0); i < amount/unit; i++ {
+			grades = append(grades, gradeIndex)
+		}
+	}
+	return grades
+}
+
+// makeMedianOrder lists the positions of sorted judgments in the order the Majority Judgment reads them:
+// the low median first, then the low median of the remaining judgments, and so on.
+func makeMedianOrder(amountOfJudgments int) []int {
+	order := make([]int, 0, amountOfJudgments)
+	low, high := 0, -1 // bounds of the positions already read
+	for remaining := amountOfJudgments; remaining > 0; remaining-- {
+		median := (remaining - 1) / 2
+		if high < low {
+			low, high = median, median
+			order = append(order, median)
+			continue
+		}
+		if median < low {
+			low--
+			order = append(order, low)
+		} else {
+			high++
+			order = append(order, high)
+		}
+	}
+	return order
+}
+
+// compareMajorityValues returns 1 if the first sorted judgments are ranked above the second ones,
+// -1 if they are ranked below, and 0 if they are tied
+func compareMajorityValues(first []int, second []int, order []int) int {
+	for _, position := range order {
+		if first[position] > second[position] {
+			return 1
+		}
+		if first[position] < second[position] {
+			return -1
+		}
+	}
+	return 0
+}
+
+// computeOneGradeMoves finds the least amount of changes of a judgment by one grade
+// that would rank the below judgments strictly above the above ones, or -1 if none would.
+//
+// Lowering the sorted judgments x of the proposal above into x' costs the sum of x - x',
+// and raising the sorted judgments y of the proposal below into y' costs the sum of y' - y.
+// The proposal below wins when x' and y' are equal on the first positions read by the Majority Judgment,
+// a block of positions around the median, and x' < y' on the next position read, either just below
+// or just above the block.  Making both equal on the block costs the sum of x - y whatever their common values,
+// and those values only bound the judgments outside the block, so we try them all, for each size of block.
+func computeOneGradeMoves(above []int, below []int, order []int, amountOfGrades int) int {
+	amountOfJudgments := len(above)
+	if 0 == amountOfJudgments || amountOfGrades < 2 {
+		return -1
+	}
+
+	// lowering[grade][position] is the cost of lowering the judgments above before the position to the grade at most
+	lowering := make([][]int, amountOfGrades)
+	// raising[grade][position] is the cost of raising the judgments below from the position to the grade at least
+	raising := make([][]int, amountOfGrades)
+	for grade := 0; grade < amountOfGrades; grade++ {
+		lowering[grade] = make([]int, amountOfJudgments+1)
+		raising[grade] = make([]int, amountOfJudgments+1)
+		for position := 0; position < amountOfJudgments; position++ {
+			lowering[grade][position+1] = lowering[grade][position] + maxInt(0, above[position]-grade)
+		}
+		for position := amountOfJudgments - 1; position >= 0; position-- {
+			raising[grade][position] = raising[grade][position+1] + maxInt(0, grade-below[position])
+		}
+	}
+
+	best := -1
+	keep := func(cost int) {
+		if -1 == best || cost < best {
+			best = cost
+		}
+	}
+
+	low, high := 0, -1 // bounds of the block
+	blockCost := 0
+	for _, position := range order {
+		x, y := above[position], below[position]
+		if high < low {
+			// No block, the median decides
+			for lowered := 0; lowered <= x; lowered++ {
+				for raised := maxInt(y, lowered+1); raised < amountOfGrades; raised++ {
+					keep((x - lowered) + lowering[lowered][position] + (raised - y) + raising[raised][position+1])
+				}
+			}
+		} else if position < low {
+			// The block is bounded by a above and b below, b being the lowest it may be
+			for a := below[low]; a <= above[low]; a++ {
+				b := maxInt(a, below[high])
+				sideCost := raising[b][high+1]
+				for raised := y; raised <= a; raised++ {
+					for lowered := 0; lowered < raised && lowered <= x; lowered++ {
+						keep(blockCost + sideCost + (raised - y) + (x - lowered) + lowering[lowered][position])
+					}
+				}
+			}
+		} else {
+			// The block is bounded by b below and a above, a being the highest it may be
+			for b := below[high]; b <= above[high]; b++ {
+				a := minInt(b, above[low])
+				sideCost := lowering[a][low]
+				for lowered := b; lowered <= x; lowered++ {
+					for raised := maxInt(maxInt(y, b), lowered+1); raised < amountOfGrades; raised++ {
+						keep(blockCost + sideCost + (x - lowered) + (raised - y) + raising[raised][position+1])
+					}
+				}
+			}
+		}
+
+		// The position joins the block, where both judgments must be made equal
+		if y > x {
+			break
+		}
+		blockCost += x - y
+		if high < low {
+			low, high = position, position
+		} else if position < low {
+			low = position
+		} else {
+			high = position
+		}
+	}
+
+	return best
+}
+
+// computeAnyGradeMoves finds the least amount of judgments changed to any grade
+// that would rank the below judgments strictly above the above ones, or -1 if none would.
+// With k changes, the best is to move the highest judgments above to the "worst" grade,
+// and the lowest judgments below to the "best" grade, in some proportion,
+// since any other change would leave judgments that dominate these.
+func computeAnyGradeMoves(above []int, below []int, order []int, amountOfGrades int) int {
+	amountOfJudgments := len(above)
+	if 0 == amountOfJudgments || amountOfGrades < 2 {
+		return -1
+	}
+
+	canSwap := func(moves int) bool {
+		lowered := make([]int, amountOfJudgments)
+		raised := make([]int, amountOfJudgments)
+		for movesAbove := 0; movesAbove <= moves; movesAbove++ {
+			movesBelow := moves - movesAbove
+			if movesAbove > amountOfJudgments || movesBelow > amountOfJudgments {
+				continue
+			}
+			for position := 0; position < amountOfJudgments; position++ {
+				lowered[position] = 0
+				if position >= movesAbove {
+					lowered[position] = above[position-movesAbove]
+				}
+				raised[position] = amountOfGrades - 1
+				if position < amountOfJudgments-movesBelow {
+					raised[position] = below[position+movesBelow]
+				}
+			}
+			if -1 == compareMajorityValues(lowered, raised, order) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if canSwap(0) {
+		return 0
+	}
+	least, most := 0, 2*amountOfJudgments // least cannot swap, most can
+	for most-least > 1 {
+		middle := (least + most) / 2
+		if canSwap(middle) {
+			most = middle
+		} else {
+			least = middle
+		}
+	}
+
+	return most
+}
+
+func computeGreatestCommonDivisor(a uint64, b uint64) uint64 {
+	for 0 != b {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
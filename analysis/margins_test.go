@@ -0,0 +1,186 @@
+package analysis
+
+import (
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"testing"
+)
+
+var marginsTestData = []struct {
+	name          string
+	above         []uint64
+	below         []uint64
+	scale         float64
+	oneGradeMoves float64
+	anyGradeMoves float64
+}{
+	{
+		name:          "Majority grades one grade apart",
+		above:         []uint64{0, 1, 2},
+		below:         []uint64{1, 2, 0},
+		oneGradeMoves: 3,
+		anyGradeMoves: 2,
+	},
+	{
+		name:          "Majority grades two grades apart",
+		above:         []uint64{0, 0, 3},
+		below:         []uint64{3, 0, 0},
+		oneGradeMoves: 6,
+		anyGradeMoves: 4,
+	},
+	{
+		name:          "Same majority grade, decided by the second group",
+		above:         []uint64{0, 2, 2},
+		below:         []uint64{1, 1, 2},
+		oneGradeMoves: 1,
+		anyGradeMoves: 1,
+	},
+	{
+		name:          "Tied proposals",
+		above:         []uint64{1, 2, 1},
+		below:         []uint64{1, 2, 1},
+		oneGradeMoves: 1,
+		anyGradeMoves: 1,
+	},
+	{
+		name:          "Five grades",
+		above:         []uint64{0, 1, 1, 2, 2},
+		below:         []uint64{2, 2, 1, 1, 0},
+		oneGradeMoves: 5,
+		anyGradeMoves: 3,
+	},
+	{
+		name:          "Scaled tallies count in judgments",
+		above:         []uint64{0, 0, 6},
+		below:         []uint64{6, 0, 0},
+		scale:         2,
+		oneGradeMoves: 6,
+		anyGradeMoves: 4,
+	},
+	{
+		name:          "A single grade",
+		above:         []uint64{3},
+		below:         []uint64{3},
+		oneGradeMoves: -1,
+		anyGradeMoves: -1,
+	},
+}
+
+func TestMargins(t *testing.T) {
+	for _, tt := range marginsTestData {
+		t.Run(tt.name, func(t *testing.T) {
+			round := makeRound(t, []string{"Above", "Below"}, [][]uint64{tt.above, tt.below})
+			if 0 != tt.scale {
+				round.Scale = tt.scale
+			}
+			margin, marginErr := computeMargin(round, 0, 1)
+			if nil != marginErr {
+				t.Fatal(marginErr)
+			}
+			if tt.oneGradeMoves != margin.OneGradeMoves || tt.anyGradeMoves != margin.AnyGradeMoves {
+				t.Errorf(
+					"expected %v one-grade moves and %v any-grade moves, got %v and %v",
+					tt.oneGradeMoves, tt.anyGradeMoves, margin.OneGradeMoves, margin.AnyGradeMoves,
+				)
+			}
+		})
+	}
+}
+
+// TestMarginsAgainstBruteForce checks the margins of all the pairs of small polls
+// against every way of changing their judgments
+func TestMarginsAgainstBruteForce(t *testing.T) {
+	const amountOfJudges = 4
+	const amountOfGrades = 3
+	tallies := listTallies(amountOfJudges, amountOfGrades)
+	for _, above := range tallies {
+		for _, below := range tallies {
+			round := makeRound(t, []string{"Above", "Below"}, [][]uint64{above, below})
+			if round.Result.Proposals[0].Rank > round.Result.Proposals[1].Rank {
+				continue
+			}
+			margin, marginErr := computeMargin(round, 0, 1)
+			if nil != marginErr {
+				t.Fatal(marginErr)
+			}
+
+			bestOneGradeMoves, bestAnyGradeMoves := -1, -1
+			for _, lowered := range tallies {
+				for _, raised := range tallies {
+					if !isRankedAbove(t, raised, lowered) {
+						continue
+					}
+					oneGradeMoves := countOneGradeMoves(above, lowered) + countOneGradeMoves(below, raised)
+					if -1 == bestOneGradeMoves || oneGradeMoves < bestOneGradeMoves {
+						bestOneGradeMoves = oneGradeMoves
+					}
+					anyGradeMoves := countAnyGradeMoves(above, lowered) + countAnyGradeMoves(below, raised)
+					if -1 == bestAnyGradeMoves || anyGradeMoves < bestAnyGradeMoves {
+						bestAnyGradeMoves = anyGradeMoves
+					}
+				}
+			}
+
+			if float64(bestOneGradeMoves) != margin.OneGradeMoves || float64(bestAnyGradeMoves) != margin.AnyGradeMoves {
+				t.Errorf(
+					"%v above %v: expected %d one-grade moves and %d any-grade moves, got %v and %v",
+					above, below, bestOneGradeMoves, bestAnyGradeMoves, margin.OneGradeMoves, margin.AnyGradeMoves,
+				)
+			}
+		}
+	}
+}
+
+// listTallies lists all the tallies of that many judges on that many grades
+func listTallies(amountOfJudges uint64, amountOfGrades int) [][]uint64 {
+	if 1 == amountOfGrades {
+		return [][]uint64{{amountOfJudges}}
+	}
+	tallies := make([][]uint64, 0)
+	for amount := uint64(0); amount <= amountOfJudges; amount++ {
+		for _, rest := range listTallies(amountOfJudges-amount, amountOfGrades-1) {
+			tallies = append(tallies, append([]uint64{amount}, rest...))
+		}
+	}
+	return tallies
+}
+
+// isRankedAbove tells whether the library ranks the first tally strictly above the second one
+func isRankedAbove(t *testing.T, first []uint64, second []uint64) bool {
+	pollTally := &judgment.PollTally{
+		AmountOfJudges: 0,
+		Proposals: []*judgment.ProposalTally{
+			{Tally: append([]uint64{}, first...)},
+			{Tally: append([]uint64{}, second...)},
+		},
+	}
+	pollTally.GuessAmountOfJudges()
+	result, deliberationErr := (&judgment.MajorityJudgment{}).Deliberate(pollTally)
+	if nil != deliberationErr {
+		t.Fatal(deliberationErr)
+	}
+	return result.Proposals[0].Rank < result.Proposals[1].Rank
+}
+
+// countOneGradeMoves is the least amount of changes by one grade turning the judgments of a tally into another's:
+// the distance between their sorted judgments
+func countOneGradeMoves(from []uint64, to []uint64) int {
+	moves := 0
+	fromCumulated, toCumulated := 0, 0
+	for gradeIndex := range from {
+		fromCumulated += int(from[gradeIndex])
+		toCumulated += int(to[gradeIndex])
+		moves += maxInt(fromCumulated-toCumulated, toCumulated-fromCumulated)
+	}
+	return moves
+}
+
+// countAnyGradeMoves is the least amount of judgments to change to turn a tally into another
+func countAnyGradeMoves(from []uint64, to []uint64) int {
+	moves := 0
+	for gradeIndex := range from {
+		if from[gradeIndex] > to[gradeIndex] {
+			moves += int(from[gradeIndex] - to[gradeIndex])
+		}
+	}
+	return moves
+}
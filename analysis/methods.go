@@ -0,0 +1,193 @@
+package analysis
+
+import (
+	"math"
+	"math/big"
+	"sort"
+)
+
+// Scores closer than this are deemed equal, since they are sums of floats made in different orders
+const scoreTolerance = 1e-9
+
+// MethodsComparison holds what other voting methods would make of the same grades,
+// to show them next to the ranking of the Majority Judgment
+type MethodsComparison struct {
+	ApprovalThreshold int  // index of the lowest grade counted as an approval
+	HasBallots        bool // whether the Condorcet and Copeland results are available
+	// Proposals in the order they were submitted
+	Proposals []MethodsResult
+}
+
+// MethodsResult is the result of a proposal with the other voting methods.
+// Ranks start at 1, and proposals with equal scores share their rank.
+type MethodsResult struct {
+	// Score (or range) voting: the mean of the indices of the grades, from 0 for the "worst" grade
+	MeanGrade float64
+	MeanRank  int
+	// Approval voting: the percentage of judgments at the approval threshold or above
+	Approval     float64
+	ApprovalRank int
+	// Borda count, estimated from the tallies: the points a judge would give the proposal, on average,
+	// if their judgments of each proposal were drawn independently from the tallies.
+	// A proposal gets a point for each other proposal it is graded above, and half a point on a tie.
+	Borda     float64
+	BordaRank int
+	// Copeland: the amount of duels won against the other proposals, plus half the amount of tied duels,
+	// where a duel is won when more judges graded the proposal above the other than the other way around.
+	// Weighted judges count as much as their weight.  Only available with ballots.
+	Copeland        float64
+	CopelandRank    int
+	CondorcetWinner bool // whether the proposal won all its duels ; there may be none
+}
+
+// CompareMethods computes the results of other voting methods from the tallies of the round,
+// and from the ballots when there are any.  The judgments are the grade indices given by each judge
+// to each proposal, -1 for no judgment, like the readers make them.
+// The weights are those of each judge, or nil when the judges are not weighted.
+func CompareMethods(round *Round, judgments [][]int, weights []*big.Rat, approvalThreshold int) *MethodsComparison {
+	amountOfProposals := len(round.Proposals)
+	comparison := &MethodsComparison{
+		ApprovalThreshold: approvalThreshold,
+		HasBallots:        nil != judgments,
+		Proposals:         make([]MethodsResult, amountOfProposals),
+	}
+
+	distributions := make([][]float64, 0, amountOfProposals)
+	for proposalIndex := 0; proposalIndex < amountOfProposals; proposalIndex++ {
+		distributions = append(distributions, readDistribution(round, proposalIndex))
+	}
+
+	means := make([]float64, amountOfProposals)
+	approvals := make([]float64, amountOfProposals)
+	bordas := make([]float64, amountOfProposals)
+	for i, distribution := range distributions {
+		for gradeIndex, share := range distribution {
+			means[i] += float64(gradeIndex) * share
+			if gradeIndex >= approvalThreshold {
+				approvals[i] += 100.0 * share
+			}
+		}
+		for j, otherDistribution := range distributions {
+			if i != j {
+				bordas[i] += computeProbabilityOfBeating(distribution, otherDistribution)
+			}
+		}
+	}
+
+	meanRanks := rankScores(means)
+	approvalRanks := rankScores(approvals)
+	bordaRanks := rankScores(bordas)
+	for i := range comparison.Proposals {
+		comparison.Proposals[i] = MethodsResult{
+			MeanGrade:    means[i],
+			MeanRank:     meanRanks[i],
+			Approval:     approvals[i],
+			ApprovalRank: approvalRanks[i],
+			Borda:        bordas[i],
+			BordaRank:    bordaRanks[i],
+		}
+	}
+
+	if comparison.HasBallots {
+		copelands := make([]float64, amountOfProposals)
+		for i := 0; i < amountOfProposals; i++ {
+			for j := i + 1; j < amountOfProposals; j++ {
+				preferringI, preferringJ := countPreferences(judgments, weights, i, j)
+				if preference := preferringI.Cmp(preferringJ); preference > 0 {
+					copelands[i]++
+				} else if preference < 0 {
+					copelands[j]++
+				} else {
+					copelands[i] += 0.5
+					copelands[j] += 0.5
+				}
+			}
+		}
+		copelandRanks := rankScores(copelands)
+		for i := range comparison.Proposals {
+			comparison.Proposals[i].Copeland = copelands[i]
+			comparison.Proposals[i].CopelandRank = copelandRanks[i]
+			comparison.Proposals[i].CondorcetWinner = copelands[i] == float64(amountOfProposals-1)
+		}
+	}
+
+	return comparison
+}
+
+// readDistribution returns the share of the judgments of the proposal on each grade, from 0 to 1
+func readDistribution(round *Round, proposalIndex int) []float64 {
+	tally := round.Tally.Proposals[proposalIndex]
+	amountOfJudgments := float64(tally.CountJudgments())
+	distribution := make([]float64, len(tally.Tally))
+	if 0 == amountOfJudgments {
+		return distribution
+	}
+	for gradeIndex, amount := range tally.Tally {
+		distribution[gradeIndex] = float64(amount) / amountOfJudgments
+	}
+	return distribution
+}
+
+// computeProbabilityOfBeating returns the probability that a judgment drawn from the first distribution
+// is above a judgment drawn from the second one, counting ties as half
+func computeProbabilityOfBeating(distribution []float64, otherDistribution []float64) float64 {
+	probability := 0.0
+	otherBelow := 0.0
+	for gradeIndex, share := range distribution {
+		probability += share * (otherBelow + 0.5*otherDistribution[gradeIndex])
+		otherBelow += otherDistribution[gradeIndex]
+	}
+	return probability
+}
+
+// countPreferences counts the judges who graded the first proposal above the second one, and the other way around,
+// each judge counting as much as their weight, or 1 when there are no weights.
+// Judges who did not judge both proposals are ignored.
+func countPreferences(
+	judgments [][]int,
+	weights []*big.Rat,
+	first int,
+	second int,
+) (preferringFirst *big.Rat, preferringSecond *big.Rat) {
+	preferringFirst, preferringSecond = new(big.Rat), new(big.Rat)
+	one := big.NewRat(1, 1)
+	for judgeIndex, judgeJudgments := range judgments {
+		if first >= len(judgeJudgments) || second >= len(judgeJudgments) {
+			continue
+		}
+		firstGrade, secondGrade := judgeJudgments[first], judgeJudgments[second]
+		if firstGrade < 0 || secondGrade < 0 {
+			continue
+		}
+		weight := one
+		if nil != weights {
+			weight = weights[judgeIndex]
+		}
+		if firstGrade > secondGrade {
+			preferringFirst.Add(preferringFirst, weight)
+		} else if secondGrade > firstGrade {
+			preferringSecond.Add(preferringSecond, weight)
+		}
+	}
+	return
+}
+
+// rankScores ranks the scores from the highest, from 1, (nearly) equal scores sharing their rank
+func rankScores(scores []float64) []int {
+	order := make([]int, 0, len(scores))
+	for i := range scores {
+		order = append(order, i)
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+
+	ranks := make([]int, len(scores))
+	for position, i := range order {
+		ranks[i] = position + 1
+		if position > 0 && math.Abs(scores[order[position-1]]-scores[i]) < scoreTolerance {
+			ranks[i] = ranks[order[position-1]]
+		}
+	}
+	return ranks
+}
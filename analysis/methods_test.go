@@ -0,0 +1,30 @@
+package analysis
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestCompareMethodsWeightedCopeland(t *testing.T) {
+	// Two judges prefer Pizza, but the one who prefers Chips weighs as much as three
+	judgments := [][]int{
+		{2, 1},
+		{1, 0},
+		{0, 2},
+	}
+	round := makeRound(t, []string{"Pizza", "Chips"}, [][]uint64{{1, 1, 1}, {1, 1, 1}})
+
+	unweighted := CompareMethods(round, judgments, nil, 1)
+	if 1 != unweighted.Proposals[0].CopelandRank || !unweighted.Proposals[0].CondorcetWinner {
+		t.Errorf("expected Pizza to win the duel of the unweighted judges, got %+v", unweighted.Proposals)
+	}
+
+	weights := []*big.Rat{big.NewRat(1, 1), big.NewRat(1, 1), big.NewRat(3, 1)}
+	weighted := CompareMethods(round, judgments, weights, 1)
+	if 1 != weighted.Proposals[1].CopelandRank || !weighted.Proposals[1].CondorcetWinner {
+		t.Errorf("expected Chips to win the duel of the weighted judges, got %+v", weighted.Proposals)
+	}
+	if weighted.Proposals[0].CondorcetWinner {
+		t.Errorf("expected Pizza not to be a Condorcet winner, got %+v", weighted.Proposals)
+	}
+}
@@ -0,0 +1,133 @@
+package analysis
+
+import (
+	"fmt"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// Policies breaking a tie that straddles the cutoff of the winners
+const (
+	TieBreakLottery = "lottery" // draw the winners among the tied proposals, with a seeded random number generator
+	TieBreakOrder   = "order"   // elect the tied proposals in the order they were submitted
+	TieBreakRefuse  = "refuse"  // elect none of them, and fail
+)
+
+// TieBreaks lists the policies breaking a tie at the cutoff, for help messages
+func TieBreaks() []string {
+	return []string{TieBreakRefuse, TieBreakLottery, TieBreakOrder}
+}
+
+// Winners are the proposals elected to the seats, from the top of the ranking
+type Winners struct {
+	Seats    int    // amount of proposals to elect
+	TieBreak string // policy breaking a tie at the cutoff, one of refuse, lottery, order
+	Seed     int64  // of the lottery breaking the tie, if any
+	Elected  []bool // whether each proposal is elected, in the order they were submitted
+	// Proposals sharing the rank at the cutoff, when there are more of them than seats left ; nil otherwise
+	Tie *WinnersTie
+}
+
+// WinnersTie is a tie that straddles the cutoff, more proposals sharing a rank than there are seats left for them
+type WinnersTie struct {
+	Rank      int   // shared by the tied proposals
+	Proposals []int // indices of the tied proposals, in the order they were submitted
+	Seats     int   // amount of seats left for them
+	Elected   []int // indices of the tied proposals elected by the tie-break, empty when it refused to
+}
+
+// TieAtCutoffError is returned when a tie straddles the cutoff, and the tie-break policy refuses to break it
+type TieAtCutoffError struct {
+	Tie       *WinnersTie
+	Proposals []string // names of all the proposals
+}
+
+// Error is part of the error interface
+func (e *TieAtCutoffError) Error() string {
+	names := make([]string, 0, len(e.Tie.Proposals))
+	for _, proposalIndex := range e.Tie.Proposals {
+		names = append(names, e.Proposals[proposalIndex])
+	}
+	return fmt.Sprintf(
+		"%s share rank #%d at the cutoff, but only %d of them may be elected, and the tie-break refuses to pick",
+		strings.Join(names, ", "), e.Tie.Rank, e.Tie.Seats,
+	)
+}
+
+// SelectWinners elects the proposals of the best ranks to the seats.
+// When more proposals share the rank at the cutoff than there are seats left,
+// the tie is broken by the policy, or a TieAtCutoffError is returned along with the winners above the tie.
+func SelectWinners(
+	result *judgment.PollResult,
+	proposals []string,
+	seats int,
+	tieBreak string,
+	seed int64,
+) (*Winners, error) {
+	amountOfProposals := len(result.ProposalsSorted)
+	if seats < 1 || seats > amountOfProposals {
+		return nil, fmt.Errorf("the amount of winners must be between 1 and %d, got %d", amountOfProposals, seats)
+	}
+	tieBreak = strings.ToLower(strings.TrimSpace(tieBreak))
+	if "" == tieBreak {
+		tieBreak = TieBreakRefuse
+	}
+	if TieBreakLottery != tieBreak && TieBreakOrder != tieBreak && TieBreakRefuse != tieBreak {
+		return nil, fmt.Errorf(
+			"tie-break `%s` is not supported.  Supported tie-breaks: %s", tieBreak, strings.Join(TieBreaks(), ", "),
+		)
+	}
+
+	winners := &Winners{
+		Seats:    seats,
+		TieBreak: tieBreak,
+		Seed:     seed,
+		Elected:  make([]bool, amountOfProposals),
+	}
+
+	// Proposals above the rank at the cutoff are elected, and so are the ones at that rank if they all fit
+	cutoffRank := result.ProposalsSorted[seats-1].Rank
+	tied := make([]int, 0, amountOfProposals)
+	seatsLeft := seats
+	for _, proposalResult := range result.ProposalsSorted {
+		if proposalResult.Rank < cutoffRank {
+			winners.Elected[proposalResult.Index] = true
+			seatsLeft--
+		} else if proposalResult.Rank == cutoffRank {
+			tied = append(tied, proposalResult.Index)
+		}
+	}
+	if len(tied) == seatsLeft {
+		for _, proposalIndex := range tied {
+			winners.Elected[proposalIndex] = true
+		}
+		return winners, nil
+	}
+
+	sort.Ints(tied)
+	winners.Tie = &WinnersTie{
+		Rank:      cutoffRank,
+		Proposals: tied,
+		Seats:     seatsLeft,
+		Elected:   make([]int, 0, seatsLeft),
+	}
+	switch tieBreak {
+	case TieBreakRefuse:
+		return winners, &TieAtCutoffError{Tie: winners.Tie, Proposals: proposals}
+	case TieBreakLottery:
+		drawn := rand.New(rand.NewSource(seed)).Perm(len(tied))[:seatsLeft]
+		sort.Ints(drawn)
+		for _, position := range drawn {
+			winners.Tie.Elected = append(winners.Tie.Elected, tied[position])
+		}
+	case TieBreakOrder:
+		winners.Tie.Elected = append(winners.Tie.Elected, tied[:seatsLeft]...)
+	}
+	for _, proposalIndex := range winners.Tie.Elected {
+		winners.Elected[proposalIndex] = true
+	}
+
+	return winners, nil
+}
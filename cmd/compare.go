@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+	"github.com/MieuxVoter/majority-judgment-cli/formatter"
+	"github.com/MieuxVoter/majority-judgment-cli/pipeline"
+	"github.com/spf13/cobra"
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare BEFORE AFTER",
+	Short: "Compare two polls, or two rounds of the same poll",
+	Long: `Compare two polls, or two rounds of the same poll, to see what moved.
+
+	mj compare january.csv february.csv
+
+Proposals are matched by their name, and for each of them are reported
+the changes of rank and of majority grade, as well as the differences of
+the amounts of judgments per grade.  New and removed proposals are listed too.
+
+Both polls must use the same amount of grades.
+The results may also be formatted as json or csv:
+
+	mj compare january.csv february.csv --format json
+`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, configErr := readConfig(lookupFlags(cmd))
+		if nil != configErr {
+			exitWithError(configErr)
+		}
+
+		var comparisonFormatter formatter.ComparisonFormatter
+		switch config.Format {
+		case "text", "txt":
+			comparisonFormatter = &formatter.TextComparisonFormatter{}
+		case "json":
+			comparisonFormatter = &formatter.JsonComparisonFormatter{}
+		case "csv":
+			comparisonFormatter = &formatter.CsvComparisonFormatter{}
+		default:
+			exitWithError(configurationError(fmt.Errorf(
+				"format `%s` is not supported by compare.  Supported formats: text, json, csv", config.Format,
+			)))
+		}
+
+		rounds := make([]*analysis.Round, 0, 2)
+		for _, fileParameter := range args {
+			poll, deliberationErr := deliberateFile(fileParameter, config)
+			if nil != deliberationErr {
+				exitWithError(deliberationErr)
+			}
+			rounds = append(rounds, &analysis.Round{
+				Tally:     poll.Tally,
+				Result:    poll.Result,
+				Proposals: poll.Proposals,
+				Grades:    poll.Grades,
+				Scale:     poll.Scale,
+			})
+		}
+
+		comparison, comparisonErr := analysis.Compare(rounds[0], rounds[1])
+		if nil != comparisonErr {
+			exitWithError(&pipeline.Error{Kind: pipeline.DeliberationError, Err: comparisonErr})
+		}
+
+		out, formatErr := comparisonFormatter.FormatComparison(comparison, &config.Options)
+		if nil != formatErr {
+			exitWithError(&pipeline.Error{Kind: pipeline.FormattingError, Err: formatErr})
+		}
+
+		writeOutput(cmd, out+"\n")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+
+	addInputFlags(compareCmd)
+	compareCmd.Flags().StringP("format", "f", "text", "one of text, json, csv")
+	compareCmd.Flags().StringP("output", "o", "", "file to write the output into (default is stdout)")
+	compareCmd.Flags().StringP("width", "w", "79", "desired width, in characters")
+	compareCmd.Flags().Bool("no-color", false, "do not use colors in the text output")
+}
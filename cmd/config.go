@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/MieuxVoter/majority-judgment-cli/formatter"
+	"github.com/MieuxVoter/majority-judgment-cli/pipeline"
+	"github.com/MieuxVoter/majority-judgment-cli/reader"
+	"github.com/spf13/cobra"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// optionsLookup returns the value of the option of that name, like the flag --name,
+// along with whether it was set or is its default value.
+// This way, options are read the same way from the flags of the command and from the query of an HTTP request.
+type optionsLookup func(name string) (value string, isSet bool)
+
+// lookupFlags reads options from the flags of the command
+func lookupFlags(cmd *cobra.Command) optionsLookup {
+	return func(name string) (string, bool) {
+		flag := cmd.Flags().Lookup(name)
+		if nil == flag {
+			return "", false
+		}
+		return flag.Value.String(), flag.Changed
+	}
+}
+
+// isEnabled tells whether a boolean option was set, like --sort, --sort=true or ?sort
+func isEnabled(lookup optionsLookup, name string) bool {
+	value, isSet := lookup(name)
+	if !isSet {
+		return false
+	}
+	enabled, parseErr := strconv.ParseBool(value)
+	return nil != parseErr || enabled
+}
+
+// readConfig reads the pipeline's configuration from the options
+func readConfig(lookup optionsLookup) (*pipeline.Config, error) {
+	value := func(name string) string {
+		v, _ := lookup(name)
+		return v
+	}
+
+	config := pipeline.NewConfig()
+	config.Format = value("format")
+	config.Chart = value("chart")
+	config.InputFormat = value("input-format")
+	config.InputKind = value("input-kind")
+	config.InvertGrades = isEnabled(lookup, "invert-input-grades")
+	config.Normalize = isEnabled(lookup, "normalize")
+	config.Default = value("default")
+	if method := value("method"); "" != method {
+		config.Method = method
+	}
+	config.CompareMethods = isEnabled(lookup, "compare-methods")
+	config.Approval = value("approval")
+	config.Margins = isEnabled(lookup, "margins")
+	// Only the root command may bootstrap
+	if bootstrapValue := value("bootstrap"); "" != bootstrapValue {
+		bootstrap, bootstrapErr := strconv.Atoi(bootstrapValue)
+		if nil != bootstrapErr || bootstrap < 0 {
+			return nil, configurationError(fmt.Errorf(
+				"unrecognized --bootstrap amount `%s`.  Use a positive integer, like so: --bootstrap 1000",
+				bootstrapValue,
+			))
+		}
+		config.Bootstrap = bootstrap
+	}
+	if seedValue := value("seed"); "" != seedValue {
+		seed, seedErr := strconv.ParseInt(seedValue, 10, 64)
+		if nil != seedErr {
+			return nil, configurationError(fmt.Errorf(
+				"unrecognized --seed `%s`.  Use an integer, like so: --seed 42", seedValue,
+			))
+		}
+		config.Seed = seed
+	}
+	if workersValue := value("workers"); "" != workersValue {
+		workers, workersErr := strconv.Atoi(workersValue)
+		if nil != workersErr || workers < 0 {
+			return nil, configurationError(fmt.Errorf(
+				"unrecognized --workers amount `%s`.  Use a positive integer, or 0 for one per CPU", workersValue,
+			))
+		}
+		config.Workers = workers
+	}
+	config.GroupBy = strings.TrimSpace(value("group-by"))
+	config.WeightColumn = value("weight-column")
+	config.StratumColumn = value("stratum-column")
+	if weightsPath := value("weights"); "" != strings.TrimSpace(weightsPath) {
+		strataWeights, weightsErr := readStrataWeightsFile(weightsPath)
+		if nil != weightsErr {
+			return nil, configurationError(fmt.Errorf("failed to read the --weights `%s`: %s", weightsPath, weightsErr))
+		}
+		config.StrataWeights = strataWeights
+	}
+	if winnersValue := value("winners"); "" != winnersValue {
+		winners, winnersErr := strconv.Atoi(winnersValue)
+		if nil != winnersErr || winners < 0 {
+			return nil, configurationError(fmt.Errorf(
+				"unrecognized --winners amount `%s`.  Use a positive integer, like so: --winners 3", winnersValue,
+			))
+		}
+		config.Winners = winners
+	}
+	if tieBreak := value("tie-break"); "" != tieBreak {
+		config.TieBreak = tieBreak
+	}
+	gradesFlag := value("grades")
+	if "" != strings.TrimSpace(gradesFlag) {
+		config.Grades = strings.Split(gradesFlag, ",")
+	}
+
+	amountOfJudgesStr := value("judges")
+	amountOfJudges, amountOfJudgesErr := strconv.ParseInt(amountOfJudgesStr, 10, 64)
+	if nil != amountOfJudgesErr || amountOfJudges < 0 {
+		return nil, configurationError(fmt.Errorf(
+			"unrecognized --judges amount `%s`.  Use a positive integer, like so: --judges 42",
+			amountOfJudgesStr,
+		))
+	}
+	config.AmountOfJudges = uint64(amountOfJudges)
+
+	delimiter, delimiterErr := readRuneFlag(value("delimiter"))
+	if nil != delimiterErr {
+		return nil, configurationError(fmt.Errorf(
+			"unrecognized --delimiter `%s`: %s", value("delimiter"), delimiterErr,
+		))
+	}
+	config.Csv.Delimiter = delimiter
+	quote, quoteErr := readRuneFlag(value("quote"))
+	if nil != quoteErr {
+		return nil, configurationError(fmt.Errorf(
+			"unrecognized --quote `%s`: %s", value("quote"), quoteErr,
+		))
+	}
+	config.Csv.Quote = quote
+	hasHeader, hasHeaderErr := reader.ParsePresence(value("header"))
+	if nil != hasHeaderErr {
+		return nil, configurationError(fmt.Errorf("unrecognized --header: %s", hasHeaderErr))
+	}
+	config.Csv.HasHeader = hasHeader
+	hasNamesColumn, hasNamesColumnErr := reader.ParsePresence(value("names-column"))
+	if nil != hasNamesColumnErr {
+		return nil, configurationError(fmt.Errorf("unrecognized --names-column: %s", hasNamesColumnErr))
+	}
+	config.Csv.HasNamesColumn = hasNamesColumn
+
+	colorize := !isEnabled(lookup, "no-color")
+	_, hasNoColorEnv := os.LookupEnv("NO_COLOR") // https://no-color.org/
+	if hasNoColorEnv {
+		colorize = false
+	}
+	desiredWidth, widthErr := strconv.Atoi(value("width"))
+	if widthErr != nil || desiredWidth < 0 {
+		desiredWidth = 79
+	}
+	config.Options = formatter.Options{
+		Colorized:  colorize,
+		Scale:      1.0,
+		Sorted:     isEnabled(lookup, "sort"),
+		Terminal:   value("terminal"),
+		Width:      desiredWidth,
+		GreenToRed: isEnabled(lookup, "green-to-red"),
+		MeritBars:  !isEnabled(lookup, "no-merit-bars"),
+		Template:   value("template"),
+	}
+
+	return config, nil
+}
+
+// readStrataWeightsFile reads the weight of each stratum from the CSV file at that path
+func readStrataWeightsFile(path string) (map[string]*big.Rat, error) {
+	file, openErr := os.Open(path)
+	if nil != openErr {
+		return nil, openErr
+	}
+	defer file.Close()
+
+	var input io.Reader = file
+	return reader.ReadStrataWeights(&input)
+}
+
+// configurationError wraps an error about the flags, so that it exits like the pipeline's own
+func configurationError(err error) error {
+	return &pipeline.Error{Kind: pipeline.ConfigurationError, Err: err}
+}
+
+// readRuneFlag reads a single character from a flag value, with some friendly aliases.
+// Returns 0 when the value is empty, meaning the character should be detected.
+func readRuneFlag(value string) (rune, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return 0, nil
+	case "tab", "\\t", "\t":
+		return '\t', nil
+	case "space", " ":
+		return ' ', nil
+	case "comma":
+		return ',', nil
+	case "semicolon":
+		return ';', nil
+	case "pipe":
+		return '|', nil
+	}
+	runes := []rune(value)
+	if 1 != len(runes) {
+		return 0, fmt.Errorf("expected a single character")
+	}
+	return runes[0], nil
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+	"github.com/MieuxVoter/majority-judgment-cli/formatter"
+	"github.com/MieuxVoter/majority-judgment-cli/pipeline"
+	"github.com/spf13/cobra"
+)
+
+var duelsCmd = &cobra.Command{
+	Use:   "duels FILE",
+	Short: "Show the duels between all the proposals, as a matrix",
+	Long: `Show the duels between all the proposals, as a matrix.
+
+	mj duels example.csv --sort
+
+Each pair of proposals is compared like the Majority Judgment does,
+and the matrix tells which proposal wins each duel, and at which grade.
+The results may also be formatted as csv or json:
+
+	mj duels example.csv --format csv
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, configErr := readConfig(lookupFlags(cmd))
+		if nil != configErr {
+			exitWithError(configErr)
+		}
+
+		var duelsFormatter formatter.DuelsFormatter
+		switch config.Format {
+		case "text", "txt":
+			duelsFormatter = &formatter.TextDuelsFormatter{}
+		case "csv":
+			duelsFormatter = &formatter.CsvDuelsFormatter{}
+		case "json":
+			duelsFormatter = &formatter.JsonDuelsFormatter{}
+		default:
+			exitWithError(configurationError(fmt.Errorf(
+				"format `%s` is not supported by duels.  Supported formats: text, csv, json", config.Format,
+			)))
+		}
+
+		poll, deliberationErr := deliberateFile(args[0], config)
+		if nil != deliberationErr {
+			exitWithError(deliberationErr)
+		}
+
+		duels, duelsErr := analysis.MakeDuels(&analysis.Round{
+			Tally:     poll.Tally,
+			Result:    poll.Result,
+			Proposals: poll.Proposals,
+			Grades:    poll.Grades,
+			Scale:     poll.Scale,
+		})
+		if nil != duelsErr {
+			exitWithError(&pipeline.Error{Kind: pipeline.DeliberationError, Err: duelsErr})
+		}
+
+		out, formatErr := duelsFormatter.FormatDuels(duels, &config.Options)
+		if nil != formatErr {
+			exitWithError(&pipeline.Error{Kind: pipeline.FormattingError, Err: formatErr})
+		}
+
+		writeOutput(cmd, out+"\n")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(duelsCmd)
+
+	addInputFlags(duelsCmd)
+	duelsCmd.Flags().StringP("format", "f", "text", "one of text, csv, json")
+	duelsCmd.Flags().StringP("output", "o", "", "file to write the output into (default is stdout)")
+	duelsCmd.Flags().StringP("width", "w", "79", "desired width, in characters")
+	duelsCmd.Flags().BoolP("sort", "s", false, "sort the proposals by rank")
+	duelsCmd.Flags().Bool("no-color", false, "do not use colors in the text output")
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+	"github.com/MieuxVoter/majority-judgment-cli/formatter"
+	"github.com/MieuxVoter/majority-judgment-cli/pipeline"
+	"github.com/spf13/cobra"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain FILE",
+	Short: "Explain in plain sentences why each proposal is ranked above the next one",
+	Long: `Explain in plain sentences why each proposal is ranked above the next one.
+
+	mj explain example.csv
+
+For each pair of proposals ranked next to each other, tells whether their
+majority grades decided, or else how their second groups did, the biggest
+groups of judgments outside the majority grade.  When those tie as well,
+each step of the tie-breaking is detailed.
+
+The explanation may also be formatted as json:
+
+	mj explain example.csv --format json
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, configErr := readConfig(lookupFlags(cmd))
+		if nil != configErr {
+			exitWithError(configErr)
+		}
+
+		var explanationFormatter formatter.ExplanationFormatter
+		switch config.Format {
+		case "text", "txt":
+			explanationFormatter = &formatter.TextExplanationFormatter{}
+		case "json":
+			explanationFormatter = &formatter.JsonExplanationFormatter{}
+		default:
+			exitWithError(configurationError(fmt.Errorf(
+				"format `%s` is not supported by explain.  Supported formats: text, json", config.Format,
+			)))
+		}
+
+		poll, deliberationErr := deliberateFile(args[0], config)
+		if nil != deliberationErr {
+			exitWithError(deliberationErr)
+		}
+
+		explanation, explanationErr := analysis.Explain(&analysis.Round{
+			Tally:     poll.Tally,
+			Result:    poll.Result,
+			Proposals: poll.Proposals,
+			Grades:    poll.Grades,
+			Scale:     poll.Scale,
+		})
+		if nil != explanationErr {
+			exitWithError(&pipeline.Error{Kind: pipeline.DeliberationError, Err: explanationErr})
+		}
+
+		out, formatErr := explanationFormatter.FormatExplanation(explanation, &config.Options)
+		if nil != formatErr {
+			exitWithError(&pipeline.Error{Kind: pipeline.FormattingError, Err: formatErr})
+		}
+
+		writeOutput(cmd, out+"\n")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+
+	addInputFlags(explainCmd)
+	explainCmd.Flags().StringP("format", "f", "text", "one of text, json")
+	explainCmd.Flags().StringP("output", "o", "", "file to write the output into (default is stdout)")
+	explainCmd.Flags().StringP("width", "w", "79", "desired width, in characters")
+	explainCmd.Flags().Bool("no-color", false, "do not use colors in the text output")
+}
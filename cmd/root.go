@@ -18,18 +18,20 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+	"github.com/MieuxVoter/majority-judgment-cli/deliberator"
 	"github.com/MieuxVoter/majority-judgment-cli/formatter"
-	"github.com/MieuxVoter/majority-judgment-cli/reader"
+	"github.com/MieuxVoter/majority-judgment-cli/pipeline"
 	"github.com/MieuxVoter/majority-judgment-cli/version"
 	"github.com/spf13/cobra"
-	"io"
+	"github.com/spf13/pflag"
 	"strings"
+	"text/tabwriter"
 
 	"os"
-	"strconv"
 
-	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
 	"github.com/spf13/viper"
 )
 
@@ -40,11 +42,14 @@ const errorReading = 2
 const errorBalancing = 3
 const errorDeliberating = 4
 const errorFormatting = 5
+const errorWriting = 6
+const errorTie = 7
 
 var rootCmd = &cobra.Command{
 	Use:     "mj FILE",
 	Version: version.GitSummary,
 	Short:   "Resolve and inspect Majority Judgment polls",
+	Args:    cobra.ArbitraryArgs, // the FILE, which would otherwise be mistaken for an unknown subcommand
 	Long: `Resolve Majority Judgment polls from an input CSV.
 
 Say you have the following tally in a CSV (or TSV) file named example.csv:
@@ -78,211 +83,104 @@ Gnuplots are meant to be piped as scripts to gnuplot http://www.gnuplot.info
 
 	mj example.csv --sort --format gnuplot | gnuplot --persist
 
-You can also use gnuplot to create a SVG file:
+You can also create SVG files directly, without gnuplot:
 
-	mj example.csv --sort --format gnuplot --terminal svg | gnuplot > merits.svg
+	mj example.csv --sort --format svg > merits.svg
+	mj example.csv --format svg --chart opinion > opinion.svg
 
-The --width parameter only applies to the default format (text).
-The --terminal parameter only applies to the gnuplot format.
+You can also provide the raw ballots, one judge per row, with grades in the cells:
 
-`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) != 1 {
-			// Our FILE positional argument is mandatory.
-			_ = cmd.Help()
-			return
-		}
-		format := cmd.Flags().Lookup("format").Value.String()
-		terminal := cmd.Flags().Lookup("terminal").Value.String()
-		defaultTo := cmd.Flags().Lookup("default").Value.String()
-		amountOfJudgesStr := cmd.Flags().Lookup("judges").Value.String()
-		chart := cmd.Flags().Lookup("chart").Value.String()
-		normalize := cmd.Flags().Lookup("normalize").Changed
-		colorize := !cmd.Flags().Lookup("no-color").Changed
-		_, hasNoColorEnv := os.LookupEnv("NO_COLOR") // https://no-color.org/
-		if hasNoColorEnv {
-			colorize = false
-		}
-		invertGrades := cmd.Flags().Lookup("invert-input-grades").Changed
-		greenToRed := cmd.Flags().Lookup("green-to-red").Changed
-
-		var outputFormatter formatter.Formatter
-		outputFormatter = &formatter.TextFormatter{}
-		if "text" == format || "txt" == format {
-			if "opinion" == chart {
-				outputFormatter = &formatter.TextOpinionFormatter{}
-			}
-		} else if "json" == format {
-			outputFormatter = &formatter.JsonFormatter{}
-		} else if "csv" == format {
-			outputFormatter = &formatter.CsvFormatter{}
-		} else if "yml" == format || "yaml" == format {
-			outputFormatter = &formatter.YamlFormatter{}
-		} else if "gnuplot" == format || "plot" == format {
-			if "merit" == chart {
-				outputFormatter = &formatter.GnuplotMeritFormatter{}
-			} else if "opinion" == chart {
-				outputFormatter = &formatter.GnuplotOpinionFormatter{}
-			} else {
-				fmt.Printf("Chart `%s` is not supported.  Supported charts: merit, opinion\n", chart)
-				os.Exit(errorConfiguring)
-			}
-		} else if "gnuplot-merit" == format || "gnuplot_merit" == format {
-			outputFormatter = &formatter.GnuplotMeritFormatter{}
-		} else if "gnuplot-opinion" == format || "gnuplot_opinion" == format {
-			outputFormatter = &formatter.GnuplotOpinionFormatter{}
-		} else if "svg" == format {
-			panic(
-				"Unsupported format." + "\n" +
-					"Try with   --format gnuplot --terminal svg   instead?" + "\n" +
-					"See issue https://github.com/MieuxVoter/majority-judgment-cli/issues/11",
-			)
-		} else {
-			fmt.Printf("Format `%s` is not supported.  Supported formats: text, csv, json, yaml, gnuplot\n", format)
-			os.Exit(errorConfiguring)
-		}
+         , Pizza, Chips,     Pasta
+    Alice,  good,  poor, excellent
+      Bob,  fair,      ,      poor
 
-		proposalsTallies := make([]*judgment.ProposalTally, 0, 10)
-
-		fileParameter := strings.TrimSpace(args[0])
-		var csvReader io.Reader
-		if "-" == fileParameter {
-			csvReader = bufio.NewReader(os.Stdin)
-		} else {
-			csvFile, errOpen := os.Open(fileParameter)
-			if errOpen != nil {
-				fmt.Println(errOpen)
-			}
-			// a bit nasty ; should we just defer close() and ignore err?
-			defer func(csvFile *os.File) {
-				errClosing := csvFile.Close()
-				if errClosing != nil {
-					fmt.Println(errClosing)
-				}
-			}(csvFile)
-			csvReader = csvFile
-		}
+	mj ballots.csv --grades "reject,poor,fair,good,very good,excellent"
 
-		var tallyReader reader.Reader
+Ballots are detected automatically when they hold grades' names ;
+use --input-kind ballots when they hold grades' indices instead.
 
-		tallyReader = reader.ProfilesCsvReader{}
+The JSON and YAML outputs may be read back as inputs:
 
-		_, tallies, proposals, grades, errReader := tallyReader.Read(&csvReader, !invertGrades)
-		if errReader != nil {
-			fmt.Printf("Failed to read input: " + errReader.Error() + "\n")
-			os.Exit(errorReading)
-		}
+	mj example.csv --format json | mj - --input-format json
+	mj results.yml --sort
 
-		if normalize {
-			for proposalTallyIndex, proposalTallyAsFloats := range tallies {
-				proposalTotal := 0.0
-				for _, gradeTallyAsFloat := range proposalTallyAsFloats {
-					proposalTotal += gradeTallyAsFloat
-				}
-				for gradeIndex, gradeTallyAsFloat := range proposalTallyAsFloats {
-					tallies[proposalTallyIndex][gradeIndex] = gradeTallyAsFloat * 100.0 / proposalTotal
-				}
-			}
-		}
+When the structure of your CSV is ambiguous, you may specify it:
 
-		maximumPrecisionScale := 1000000.0
-		precisionScale := 1.0
-		for _, proposalTallyAsFloats := range tallies {
-			for _, gradeTallyAsFloat := range proposalTallyAsFloats {
-				if precisionScale >= maximumPrecisionScale {
-					break
-				}
-				for float64(uint64(gradeTallyAsFloat*precisionScale)) != gradeTallyAsFloat*precisionScale {
-					if precisionScale >= maximumPrecisionScale {
-						break
-					}
-					precisionScale *= 10.0
-				}
-			}
-			if precisionScale >= maximumPrecisionScale {
-				break
-			}
-		}
+	mj years.csv --delimiter ";" --quote "'" --header yes --names-column yes
 
-		for _, proposalTallyAsFloats := range tallies {
-			proposalTallyAsInts := make([]uint64, 0, 7)
-			for _, gradeTallyAsFloat := range proposalTallyAsFloats {
-				proposalTallyAsInts = append(proposalTallyAsInts, uint64(gradeTallyAsFloat*precisionScale))
-			}
-			proposalTally := &judgment.ProposalTally{Tally: proposalTallyAsInts}
-			proposalsTallies = append(proposalsTallies, proposalTally)
-		}
+Markdown tables may be pasted into issues and wiki pages:
 
-		poll := &judgment.PollTally{
-			Proposals: proposalsTallies,
-		}
+	mj example.csv --sort --format markdown
 
-		amountOfJudges, amountOfJudgesErr := strconv.ParseInt(amountOfJudgesStr, 10, 64)
-		if nil != amountOfJudgesErr || amountOfJudges < 0 {
-			fmt.Printf("Unrecognized --judges amount `%s`.  "+
-				"Use a positive integer, like so: --judges 42\n", amountOfJudgesStr)
-			os.Exit(errorConfiguring)
-		}
+A self-contained HTML report may be published, emailed or archived:
 
-		if amountOfJudges > 0 {
-			poll.AmountOfJudges = uint64(amountOfJudges)
-		} else {
-			poll.GuessAmountOfJudges()
-		}
+	mj example.csv --sort --format html > results.html
+
+Vega-Lite specifications may be embedded in Jupyter or Observable notebooks:
+
+	mj example.csv --sort --format vega-lite --chart opinion > opinion.vl.json
 
-		var balancerErr error
-		defaultGradeIndex := indexOf(defaultTo, grades)
-		if -1 == defaultGradeIndex {
-			if "majority" == defaultTo || "median" == defaultTo {
-				balancerErr = poll.BalanceWithMedianDefault()
-			} else {
-				defaultGrade, defaultToErr := reader.ReadNumber(defaultTo)
-				if nil != defaultToErr {
-					fmt.Printf("Unrecognized --default grade `%s`.\n", defaultTo)
-					os.Exit(errorConfiguring)
-				}
-				balancerErr = poll.BalanceWithStaticDefault(uint8(defaultGrade))
-			}
-		} else {
-			balancerErr = poll.BalanceWithStaticDefault(uint8(defaultGradeIndex))
+A LaTeX tabular and PGFPlots chart may be \input into a paper:
+
+	mj example.csv --sort --format latex > results.tex
+
+Or even PNG images, for chat messages and slides:
+
+	mj example.csv --sort --format png --output merits.png
+
+To list all the available formats:
+
+	mj --format help
+
+You may also shape the output yourself, with a Go text/template:
+
+	mj example.csv --format template --template example/template.tmpl
+
+More formats may be provided by plugins, executables named mj-format-<name>
+found in the --plugins-dir (also read from the config file) or in the PATH:
+
+	mj example.csv --format slides --plugins-dir ~/.mj/plugins
+
+The --width parameter applies to the text, svg and png formats.
+The --terminal parameter only applies to the gnuplot format.
+
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if "help" == cmd.Flags().Lookup("format").Value.String() {
+			printFormats(viper.GetString("plugins-dir"))
+			return
 		}
-		if balancerErr != nil {
-			fmt.Println("Balancing Error:", balancerErr)
-			os.Exit(errorBalancing)
+		if len(args) != 1 {
+			_ = cmd.Help()
+			return
 		}
 
-		mj := &judgment.MajorityJudgment{}
-		result, deliberationErr := mj.Deliberate(poll)
-		if deliberationErr != nil {
-			fmt.Println("Deliberation Error:", deliberationErr)
-			os.Exit(errorDeliberating)
+		config, configErr := readConfig(lookupFlags(cmd))
+		if nil != configErr {
+			exitWithError(configErr)
 		}
+		config.PluginsDir = viper.GetString("plugins-dir")
 
-		desiredWidth, widthErr := strconv.Atoi(cmd.Flags().Lookup("width").Value.String())
-		if widthErr != nil || desiredWidth < 0 {
-			desiredWidth = 79
+		// Fail early on an unknown format, before reading a possibly long input
+		outputFormatter, formatterErr := pipeline.CreateFormatter(config)
+		if nil != formatterErr {
+			exitWithError(formatterErr)
 		}
-		options := &formatter.Options{
-			Colorized:  colorize,
-			Scale:      precisionScale,
-			Sorted:     cmd.Flags().Lookup("sort").Changed,
-			Terminal:   terminal,
-			Width:      desiredWidth,
-			GreenToRed: greenToRed,
+
+		poll, deliberationErr := deliberateFile(args[0], config)
+		if nil != deliberationErr {
+			exitWithError(deliberationErr)
 		}
 
-		out, formatterErr := outputFormatter.Format(
-			poll,
-			result,
-			proposals,
-			grades,
-			options,
-		)
-		if formatterErr != nil {
-			fmt.Println("Formatter Error:", formatterErr)
-			os.Exit(errorFormatting)
+		out, formatErr := pipeline.Format(poll, config)
+		if nil != formatErr {
+			exitWithError(formatErr)
+		}
+
+		if !pipeline.IsBinary(outputFormatter) {
+			out += "\n"
 		}
-		fmt.Println(out)
+		writeOutput(cmd, out)
 	},
 }
 
@@ -292,6 +190,24 @@ func Execute() {
 	cobra.CheckErr(rootCmd.Execute())
 }
 
+// ResetFlags sets the flags of all the commands back to their defaults,
+// so that the commands may be executed more than once in the same process, like benchmarks do.
+func ResetFlags() {
+	resetCommandFlags(rootCmd)
+}
+
+func resetCommandFlags(command *cobra.Command) {
+	resetFlag := func(flag *pflag.Flag) {
+		cobra.CheckErr(flag.Value.Set(flag.DefValue))
+		flag.Changed = false
+	}
+	command.PersistentFlags().VisitAll(resetFlag)
+	command.Flags().VisitAll(resetFlag)
+	for _, subCommand := range command.Commands() {
+		resetCommandFlags(subCommand)
+	}
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
 
@@ -299,20 +215,52 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here, will be global for our application.
 
 	rootCmd.PersistentFlags().StringVar(&configurationFilePath, "config", "", "config file (default is $HOME/.mj.yaml)")
-	rootCmd.Flags().StringP("format", "f", "text", "desired format of the output")
+	addInputFlags(rootCmd)
+	rootCmd.Flags().StringP("format", "f", "text", "desired format of the output (use help to list them)")
+	rootCmd.Flags().StringP("output", "o", "", "file to write the output into (default is stdout)")
+	rootCmd.Flags().String("plugins-dir", "", "directory of the mj-format-<name> plugins, searched before the PATH")
+	cobra.CheckErr(viper.BindPFlag("plugins-dir", rootCmd.Flags().Lookup("plugins-dir")))
+	rootCmd.Flags().StringP("template", "t", "", "path to your text/template, for the template format")
 	rootCmd.Flags().StringP("terminal", "", "x11", "terminal for gnuplot (x11, qt, svg…)")
-	rootCmd.Flags().StringP("default", "d", "0", "default grade to use when unbalanced")
 	rootCmd.Flags().StringP("width", "w", "79", "desired width, in characters")
 	rootCmd.Flags().StringP("chart", "c", "merit", "one of merit, opinion")
-	rootCmd.Flags().Int64P("judges", "j", 0, "amount of judges participating (overrides our guess)")
+	rootCmd.Flags().StringP("method", "m", deliberator.MajorityJudgment, "deliberation method, one of "+
+		strings.Join(deliberator.Methods(), ", "))
 	rootCmd.Flags().BoolP("sort", "s", false, "sort proposals by their rank")
-	rootCmd.Flags().BoolP("normalize", "n", false, "normalize input to balance proposal participation")
+	rootCmd.Flags().Bool("compare-methods", false, "also show the results of other voting methods, like approval")
+	rootCmd.Flags().String("approval", "", "lowest grade counted as an approval, by name or index (default is the middle one)")
+	rootCmd.Flags().Bool("margins", false, "also show how many judgments would have to change to swap proposals")
+	rootCmd.Flags().Int("bootstrap", 0, "amount of polls to resample from the judges, to show the uncertainty of the ranks")
+	rootCmd.Flags().Int64("seed", 0, "seed of the random resampling of --bootstrap and of the lottery of --tie-break")
+	rootCmd.Flags().Int("workers", 0, "amount of parallel workers of --bootstrap (default is one per CPU)")
+	rootCmd.Flags().String("group-by", "", "column of the ballots to split the judges by, to show the results of each group")
+	rootCmd.Flags().Int("winners", 0, "amount of proposals to elect from the top of the ranking")
+	rootCmd.Flags().String("tie-break", analysis.TieBreakRefuse, "how to break a tie at the cutoff of --winners, one of "+
+		strings.Join(analysis.TieBreaks(), ", ")+" (refuse exits with code 7)")
 	rootCmd.Flags().Bool("no-color", false, "do not use colors in the text outputs")
-	rootCmd.Flags().Bool("invert-input-grades", false, "if you provide your grades from best to worst")
 	rootCmd.Flags().Bool("green-to-red", false, "display grades from best (green) to worst (red)")
+	rootCmd.Flags().Bool("no-merit-bars", false, "do not draw the merit profiles in the markdown tables")
 	rootCmd.SetVersionTemplate("{{.Version}}\n" + version.BuildDate + "\n")
 }
 
+// addInputFlags adds the flags about reading and deliberating polls, shared by the commands reading polls
+func addInputFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("default", "d", "0", "default grade to use when unbalanced")
+	cmd.Flags().Int64P("judges", "j", 0, "amount of judges participating (overrides our guess)")
+	cmd.Flags().BoolP("normalize", "n", false, "normalize input to balance proposal participation")
+	cmd.Flags().Bool("invert-input-grades", false, "if you provide your grades from best to worst")
+	cmd.Flags().StringP("input-format", "i", "auto", "one of auto, csv, json, yaml")
+	cmd.Flags().StringP("input-kind", "k", "auto", "one of auto, profiles, ballots (csv only)")
+	cmd.Flags().String("delimiter", "", "delimiter between values in the CSV input, like , ; tab or space (default is detected)")
+	cmd.Flags().String("quote", "", "quote around values of the CSV input (default is \")")
+	cmd.Flags().String("header", "auto", "whether the CSV input has a header row: auto, yes, no")
+	cmd.Flags().String("names-column", "auto", "whether the CSV input has a names column: auto, yes, no")
+	cmd.Flags().StringP("grades", "g", "", "comma-separated names of the grades used in ballots, from worst to best")
+	cmd.Flags().String("weight-column", "weight", "name of the column of the weights of the judges, in ballots")
+	cmd.Flags().String("stratum-column", "stratum", "name of the column of the strata of the judges, in ballots")
+	cmd.Flags().String("weights", "", "CSV file of the weight of each stratum, like urban,0.8 (ballots only)")
+}
+
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 	if configurationFilePath != "" {
@@ -337,13 +285,118 @@ func initConfig() {
 	}
 }
 
-// indexOf searches the data for the element, and returns its index, or -1
-// Go's typing is pretty strict, hence the need for a grunt function like this.
-func indexOf(element string, data []string) int {
-	for k, v := range data {
-		if element == v {
-			return k
+// deliberateFile reads the poll in the file, or in stdin when the file is -, and deliberates it
+func deliberateFile(fileParameter string, config *pipeline.Config) (*pipeline.Poll, error) {
+	fileParameter = strings.TrimSpace(fileParameter)
+	if "-" == fileParameter {
+		poll, deliberationErr := pipeline.Deliberate(bufio.NewReader(os.Stdin), config)
+		if nil != poll {
+			printWarnings(poll)
+		}
+		return poll, deliberationErr
+	}
+
+	inputFile, errOpen := os.Open(fileParameter)
+	if errOpen != nil {
+		return nil, &pipeline.Error{Kind: pipeline.ReadingError, Err: errOpen}
+	}
+	defer func(inputFile *os.File) {
+		errClosing := inputFile.Close()
+		if errClosing != nil {
+			fmt.Println(errClosing)
+		}
+	}(inputFile)
+
+	fileConfig := *config
+	fileConfig.InputName = fileParameter
+
+	poll, deliberationErr := pipeline.Deliberate(inputFile, &fileConfig)
+	if nil != poll {
+		printWarnings(poll)
+	}
+	return poll, deliberationErr
+}
+
+// printWarnings prints the warnings of the deliberation on stderr, so that they do not mingle with the output
+func printWarnings(poll *pipeline.Poll) {
+	for _, warning := range poll.Warnings {
+		_, _ = fmt.Fprintln(os.Stderr, "Warning:", warning)
+	}
+}
+
+// writeOutput writes the output to stdout, or to the file of the --output flag
+func writeOutput(cmd *cobra.Command, out string) {
+	var writeErr error
+	outputPath := cmd.Flags().Lookup("output").Value.String()
+	if "" == outputPath || "-" == outputPath {
+		_, writeErr = os.Stdout.WriteString(out)
+	} else {
+		writeErr = os.WriteFile(outputPath, []byte(out), 0644)
+	}
+	if writeErr != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "Writing Error:", writeErr)
+		os.Exit(errorWriting)
+	}
+}
+
+// exitWithError prints the error on stderr and exits with the code matching the failed step
+func exitWithError(err error) {
+	var pipelineErr *pipeline.Error
+	if !errors.As(err, &pipelineErr) {
+		_, _ = fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(errorConfiguring)
+	}
+
+	switch pipelineErr.Kind {
+	case pipeline.ReadingError:
+		_, _ = fmt.Fprintln(os.Stderr, "Failed to read input:", pipelineErr)
+		os.Exit(errorReading)
+	case pipeline.BalancingError:
+		_, _ = fmt.Fprintln(os.Stderr, "Balancing Error:", pipelineErr)
+		os.Exit(errorBalancing)
+	case pipeline.DeliberationError:
+		_, _ = fmt.Fprintln(os.Stderr, "Deliberation Error:", pipelineErr)
+		os.Exit(errorDeliberating)
+	case pipeline.FormattingError:
+		_, _ = fmt.Fprintln(os.Stderr, "Formatter Error:", pipelineErr)
+		os.Exit(errorFormatting)
+	case pipeline.TieError:
+		_, _ = fmt.Fprintln(os.Stderr, "Tie Error:", pipelineErr)
+		os.Exit(errorTie)
+	default:
+		_, _ = fmt.Fprintln(os.Stderr, "Configuration Error:", pipelineErr)
+		os.Exit(errorConfiguring)
+	}
+}
+
+// printFormats lists the formats of the registry and the plugins, for --format help
+func printFormats(pluginsDir string) {
+	fmt.Println("Supported formats:")
+	fmt.Println()
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "  FORMAT\tALIASES\tCHART\tDESCRIPTION")
+	for _, registration := range formatter.Registrations() {
+		chart := registration.Chart
+		if "" == chart {
+			chart = "-"
+		}
+		_, _ = fmt.Fprintf(
+			writer,
+			"  %s\t%s\t%s\t%s\n",
+			registration.Name,
+			strings.Join(registration.Aliases, ", "),
+			chart,
+			registration.Description,
+		)
+	}
+	for _, plugin := range formatter.ListPlugins(pluginsDir) {
+		if formatter.IsRegistered(plugin) {
+			continue
 		}
+		_, _ = fmt.Fprintf(writer, "  %s\t\t-\tplugin mj-format-%s\n", plugin, plugin)
 	}
-	return -1
+	_ = writer.Flush()
+	fmt.Println()
+	fmt.Println("Formats drawing several charts pick one with --chart, or with a suffix like gnuplot-opinion.")
+	fmt.Println("Executables named mj-format-<name> in the --plugins-dir or in the PATH provide more formats.")
 }
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/MieuxVoter/majority-judgment-cli/formatter"
+	"github.com/MieuxVoter/majority-judgment-cli/pipeline"
+	"github.com/MieuxVoter/majority-judgment-cli/version"
+	"github.com/spf13/cobra"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Options of the root command that may be given as query parameters to the deliberation endpoint.
+// The others, like --template or --plugins-dir, would let clients read files or run programs on the server.
+var servedOptions = map[string]bool{
+	"format":              true,
+	"chart":               true,
+	"method":              true,
+	"compare-methods":     true,
+	"approval":            true,
+	"margins":             true,
+	"group-by":            true,
+	"winners":             true,
+	"tie-break":           true,
+	"default":             true,
+	"judges":              true,
+	"width":               true,
+	"sort":                true,
+	"normalize":           true,
+	"invert-input-grades": true,
+	"green-to-red":        true,
+	"no-merit-bars":       true,
+	"input-format":        true,
+	"input-kind":          true,
+	"grades":              true,
+	"delimiter":           true,
+	"quote":               true,
+	"header":              true,
+	"names-column":        true,
+	"weight-column":       true,
+	"stratum-column":      true,
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the deliberation of polls over HTTP",
+	Long: `Serve the deliberation of polls over HTTP, for front-ends and other services.
+
+	mj serve --address 127.0.0.1:8080
+
+Post the tally (CSV, JSON or YAML) to /deliberate,
+with the flags of mj as query parameters:
+
+	curl --data-binary @example.csv "http://127.0.0.1:8080/deliberate?format=json&sort"
+
+The response has the Content-Type of the format.
+GET /health responds 200 while the server is up.
+
+The server shuts down gracefully on SIGINT and SIGTERM.
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		address := cmd.Flags().Lookup("address").Value.String()
+		maxBodySize, _ := cmd.Flags().GetInt64("max-body-size")
+		shutdownTimeout, _ := cmd.Flags().GetDuration("shutdown-timeout")
+
+		server := &http.Server{
+			Addr:              address,
+			Handler:           newServeHandler(maxBodySize),
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			WriteTimeout:      60 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		serverErr := make(chan error, 1)
+		go func() {
+			_, _ = fmt.Fprintln(os.Stderr, "Serving on", address)
+			serverErr <- server.ListenAndServe()
+		}()
+
+		select {
+		case err := <-serverErr:
+			if !errors.Is(err, http.ErrServerClosed) {
+				_, _ = fmt.Fprintln(os.Stderr, "Serving Error:", err)
+				os.Exit(errorConfiguring)
+			}
+		case <-ctx.Done():
+			_, _ = fmt.Fprintln(os.Stderr, "Shutting down…")
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			if err := server.Shutdown(shutdownCtx); err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, "Shutdown Error:", err)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringP("address", "a", "127.0.0.1:8080", "address to listen to, like :8080 for all interfaces")
+	serveCmd.Flags().Int64("max-body-size", 10<<20, "maximum size of the posted tallies, in bytes")
+	serveCmd.Flags().Duration("shutdown-timeout", 10*time.Second, "time given to pending requests when shutting down")
+}
+
+// newServeHandler creates the handler of the HTTP API, apart from the server so that it may be tested
+func newServeHandler(maxBodySize int64) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", handleHealth)
+	mux.HandleFunc("/deliberate", func(w http.ResponseWriter, r *http.Request) {
+		handleDeliberate(w, r, maxBodySize)
+	})
+
+	return mux
+}
+
+// handleHealth responds whether the server is up, for load balancers and such
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	if http.MethodGet != r.Method && http.MethodHead != r.Method {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "use GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"status":  "ok",
+		"version": version.GitSummary,
+	})
+}
+
+// handleDeliberate reads the posted tally and responds with the formatted results
+func handleDeliberate(w http.ResponseWriter, r *http.Request, maxBodySize int64) {
+	if http.MethodPost != r.Method {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "use POST, with the tally as body", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, readErr := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if nil != readErr {
+		http.Error(w, "failed to read the body: "+readErr.Error(), http.StatusBadRequest)
+		return
+	}
+	if int64(len(body)) > maxBodySize {
+		http.Error(w, fmt.Sprintf("the body exceeds %d bytes", maxBodySize), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	query := r.URL.Query()
+	config, configErr := readConfig(lookupQuery(query))
+	if nil != configErr {
+		http.Error(w, configErr.Error(), http.StatusBadRequest)
+		return
+	}
+	config.Options.Colorized = false
+	if !query.Has("input-format") {
+		config.InputFormat = detectInputFormat(r.Header.Get("Content-Type"))
+	}
+
+	// Only registered formats are served, not plugins, and not templates since they are files of the server
+	registration, findErr := formatter.Find(config.Format, config.Chart)
+	if nil != findErr {
+		http.Error(w, findErr.Error(), http.StatusBadRequest)
+		return
+	}
+	if "template" == registration.Name {
+		http.Error(w, "the template format is not served", http.StatusBadRequest)
+		return
+	}
+
+	poll, deliberationErr := pipeline.Deliberate(bytes.NewReader(body), config)
+	if nil != deliberationErr {
+		http.Error(w, deliberationErr.Error(), getHttpStatus(deliberationErr))
+		return
+	}
+	out, formatErr := pipeline.Format(poll, config)
+	if nil != formatErr {
+		http.Error(w, formatErr.Error(), getHttpStatus(formatErr))
+		return
+	}
+	if !pipeline.IsBinary(registration.Create()) {
+		out += "\n"
+	}
+
+	w.Header().Set("Content-Type", registration.MediaType)
+	_, _ = w.Write([]byte(out))
+}
+
+// lookupQuery reads the options from the query of an HTTP request, like ?format=json&sort
+func lookupQuery(query url.Values) optionsLookup {
+	return func(name string) (string, bool) {
+		if servedOptions[name] && query.Has(name) {
+			return query.Get(name), true
+		}
+		flag := rootCmd.Flags().Lookup(name)
+		if nil == flag {
+			return "", false
+		}
+		return flag.DefValue, false
+	}
+}
+
+// detectInputFormat guesses the input format from the Content-Type of the request, if any
+func detectInputFormat(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv", "text/tab-separated-values":
+		return "csv"
+	case "application/json":
+		return "json"
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return "yaml"
+	}
+	return "auto"
+}
+
+// getHttpStatus picks the status of the response from the step of the pipeline that failed
+func getHttpStatus(err error) int {
+	var pipelineErr *pipeline.Error
+	if !errors.As(err, &pipelineErr) {
+		return http.StatusInternalServerError
+	}
+
+	switch pipelineErr.Kind {
+	case pipeline.ConfigurationError, pipeline.ReadingError:
+		return http.StatusBadRequest
+	case pipeline.BalancingError, pipeline.DeliberationError:
+		return http.StatusUnprocessableEntity
+	case pipeline.TieError:
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+var serveTestData = []struct {
+	name        string
+	method      string
+	target      string
+	contentType string
+	body        string
+	code        int
+	mediaType   string
+	contains    string
+}{
+	{
+		name:      "Health",
+		method:    http.MethodGet,
+		target:    "/health",
+		code:      http.StatusOK,
+		mediaType: "application/json",
+		contains:  `"status":"ok"`,
+	},
+	{
+		name:      "CSV to default text",
+		method:    http.MethodPost,
+		target:    "/deliberate",
+		body:      "@../example/example.csv",
+		code:      http.StatusOK,
+		mediaType: "text/plain; charset=utf-8",
+		contains:  "Legend:",
+	},
+	{
+		name:      "CSV to JSON",
+		method:    http.MethodPost,
+		target:    "/deliberate?format=json&sort",
+		body:      "@../example/example.csv",
+		code:      http.StatusOK,
+		mediaType: "application/json",
+		contains:  `"proposals":["Pizza","Chips","Pasta"]`,
+	},
+	{
+		name:        "JSON to CSV, with the input format from the content type",
+		method:      http.MethodPost,
+		target:      "/deliberate?format=csv&sort=true",
+		contentType: "application/json",
+		body:        `{"proposals": ["Tea", "Coffee"], "tally": [[1, 2, 3], [3, 2, 1]]}`,
+		code:        http.StatusOK,
+		mediaType:   "text/csv; charset=utf-8",
+		contains:    "1,Tea,",
+	},
+	{
+		name:      "SVG opinion chart",
+		method:    http.MethodPost,
+		target:    "/deliberate?format=svg&chart=opinion",
+		body:      "@../example/example.csv",
+		code:      http.StatusOK,
+		mediaType: "image/svg+xml",
+		contains:  "Opinion Profile",
+	},
+	{
+		name:      "Text merit profiles for an unknown chart",
+		method:    http.MethodPost,
+		target:    "/deliberate?format=text&chart=nope",
+		body:      "@../example/example.csv",
+		code:      http.StatusOK,
+		mediaType: "text/plain; charset=utf-8",
+		contains:  "Legend:",
+	},
+	{
+		name:   "Unknown chart of the gnuplot format",
+		method: http.MethodPost,
+		target: "/deliberate?format=gnuplot&chart=nope",
+		body:   "@../example/example.csv",
+		code:   http.StatusBadRequest,
+	},
+	{
+		name:      "Usual Judgment",
+		method:    http.MethodPost,
+		target:    "/deliberate?format=csv&sort&method=usual",
+		body:      "@../example/example.csv",
+		code:      http.StatusOK,
+		mediaType: "text/csv; charset=utf-8",
+		contains:  "1,Chips,3.250000000,good",
+	},
+	{
+		name:      "Margins",
+		method:    http.MethodPost,
+		target:    "/deliberate?format=csv&sort&margins",
+		body:      "@../example/example.csv",
+		code:      http.StatusOK,
+		mediaType: "text/csv; charset=utf-8",
+		contains:  "1,Chips,323411120514016016,good,very good,2,1,,",
+	},
+	{
+		name:      "Weighted ballots",
+		method:    http.MethodPost,
+		target:    "/deliberate?format=json&grades=reject,poor,fair,good,very%20good,excellent",
+		body:      "@../example/example21.csv",
+		code:      http.StatusOK,
+		mediaType: "application/json",
+		contains:  `"weighted":true,"unweightedTally":{"amountOfJudges":6,`,
+	},
+	{
+		name:      "Group by",
+		method:    http.MethodPost,
+		target:    "/deliberate?format=csv&group-by=department&grades=reject,poor,fair,good,very%20good,excellent",
+		body:      "@../example/example22.csv",
+		code:      http.StatusOK,
+		mediaType: "text/csv; charset=utf-8",
+		contains:  "3,Chips,204110309406008507,fair,poor,1,very good,3,poor,1,3,true",
+	},
+	{
+		name:      "Winners",
+		method:    http.MethodPost,
+		target:    "/deliberate?format=csv&sort&winners=2&tie-break=order",
+		body:      "@../example/example23.csv",
+		code:      http.StatusOK,
+		mediaType: "text/csv; charset=utf-8",
+		contains:  "2,Carla,222310119413017515,fair,good,false",
+	},
+	{
+		name:      "Winners by lottery",
+		method:    http.MethodPost,
+		target:    "/deliberate?format=json&winners=2&tie-break=lottery&seed=2",
+		body:      "@../example/example23.csv",
+		code:      http.StatusOK,
+		mediaType: "application/json",
+		contains:  `"elected":[0,2],"tie":{"rank":2,"proposals":[1,2],"seats":1,"elected":[2]}`,
+	},
+	{
+		name:      "Winners by order",
+		method:    http.MethodPost,
+		target:    "/deliberate?format=json&winners=2&tie-break=order",
+		body:      "@../example/example23.csv",
+		code:      http.StatusOK,
+		mediaType: "application/json",
+		contains:  `"elected":[0,1],"tie":{"rank":2,"proposals":[1,2],"seats":1,"elected":[1]}`,
+	},
+	{
+		name:      "Winners are marked in SVG",
+		method:    http.MethodPost,
+		target:    "/deliberate?format=svg&winners=2&tie-break=order",
+		body:      "@../example/example23.csv",
+		code:      http.StatusOK,
+		mediaType: "image/svg+xml",
+		contains:  `font-weight="bold">Bruno ✓</text>`,
+	},
+	{
+		name:     "Tie at the cutoff of the winners",
+		method:   http.MethodPost,
+		target:   "/deliberate?winners=2",
+		body:     "@../example/example23.csv",
+		code:     http.StatusConflict,
+		contains: "Bruno, Carla share rank #2 at the cutoff",
+	},
+	{
+		name:   "Unknown method",
+		method: http.MethodPost,
+		target: "/deliberate?method=nope",
+		body:   "@../example/example.csv",
+		code:   http.StatusBadRequest,
+	},
+	{
+		name:   "Unknown format",
+		method: http.MethodPost,
+		target: "/deliberate?format=nope",
+		body:   "@../example/example.csv",
+		code:   http.StatusBadRequest,
+	},
+	{
+		name:   "Templates are files of the server",
+		method: http.MethodPost,
+		target: "/deliberate?format=template&template=../example/template.tmpl",
+		body:   "@../example/example.csv",
+		code:   http.StatusBadRequest,
+	},
+	{
+		name:   "Unreadable input",
+		method: http.MethodPost,
+		target: "/deliberate?input-format=json",
+		body:   "{ nope",
+		code:   http.StatusBadRequest,
+	},
+	{
+		name:        "Negative grade in judgments",
+		method:      http.MethodPost,
+		target:      "/deliberate",
+		contentType: "application/json",
+		body:        `{"proposals": ["Tea", "Coffee"], "grades": ["bad", "good"], "judgments": [[0, 1], [1, -1]]}`,
+		code:        http.StatusBadRequest,
+		contains:    "judge #2 on proposal `Coffee`",
+	},
+	{
+		name:   "Body too large",
+		method: http.MethodPost,
+		target: "/deliberate",
+		body:   strings.Repeat("a,1,2,3\n", 1000),
+		code:   http.StatusRequestEntityTooLarge,
+	},
+	{
+		name:   "Wrong method",
+		method: http.MethodGet,
+		target: "/deliberate",
+		code:   http.StatusMethodNotAllowed,
+	},
+}
+
+func TestServe(t *testing.T) {
+	handler := newServeHandler(4096)
+	for _, tt := range serveTestData {
+		t.Run(tt.name, func(t *testing.T) {
+			body := tt.body
+			if strings.HasPrefix(body, "@") {
+				fileBytes, readErr := os.ReadFile(strings.TrimPrefix(body, "@"))
+				if nil != readErr {
+					t.Fatal(readErr)
+				}
+				body = string(fileBytes)
+			}
+
+			request := httptest.NewRequest(tt.method, tt.target, strings.NewReader(body))
+			if "" != tt.contentType {
+				request.Header.Set("Content-Type", tt.contentType)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if tt.code != recorder.Code {
+				t.Fatalf("expected status %d, got %d: %s", tt.code, recorder.Code, recorder.Body.String())
+			}
+			if "" != tt.mediaType && tt.mediaType != recorder.Header().Get("Content-Type") {
+				t.Errorf("expected Content-Type %s, got %s", tt.mediaType, recorder.Header().Get("Content-Type"))
+			}
+			if !strings.Contains(recorder.Body.String(), tt.contains) {
+				t.Errorf("expected the body to contain %s, got %s", tt.contains, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Package deliberator provides the deliberation methods of the --method flag.
+// Besides the Majority Judgment of the judgment library, it implements the Usual, Typical and Central Judgments,
+// which rank proposals by their median grade, and break ties with a numeric score derived from the proportions
+// of judgments above and below it.  All of them implement judgment.DeliberatorInterface, on the same PollTally.
+package deliberator
+
+import (
+	"fmt"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"strings"
+)
+
+// Names of the deliberation methods, as in --method <name>
+const (
+	MajorityJudgment = "majority"
+	UsualJudgment    = "usual"
+	TypicalJudgment  = "typical"
+	CentralJudgment  = "central"
+)
+
+// Methods lists the names of the deliberation methods, the default one first
+func Methods() []string {
+	return []string{MajorityJudgment, UsualJudgment, TypicalJudgment, CentralJudgment}
+}
+
+// Create the deliberator of the method, Majority Judgment when the method is empty
+func Create(method string) (judgment.DeliberatorInterface, error) {
+	switch strings.ToLower(strings.TrimSpace(method)) {
+	case "", MajorityJudgment, "mj":
+		return &judgment.MajorityJudgment{}, nil
+	case UsualJudgment:
+		return &GaugeJudgment{Gauge: UsualGauge}, nil
+	case TypicalJudgment:
+		return &GaugeJudgment{Gauge: TypicalGauge}, nil
+	case CentralJudgment:
+		return &GaugeJudgment{Gauge: CentralGauge}, nil
+	}
+
+	return nil, fmt.Errorf(
+		"method `%s` is not supported.  Supported methods: %s",
+		method, strings.Join(Methods(), ", "),
+	)
+}
+
+// IsMajorityJudgment tells whether the method is the default one, whose scores are not meant for humans
+func IsMajorityJudgment(method string) bool {
+	switch strings.ToLower(strings.TrimSpace(method)) {
+	case "", MajorityJudgment, "mj":
+		return true
+	}
+	return false
+}
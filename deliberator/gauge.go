@@ -0,0 +1,128 @@
+package deliberator
+
+import (
+	"fmt"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// scoreDecimals is the amount of decimals of the scores, as displayed.
+// Proposals are ranked on their exact scores, though.
+const scoreDecimals = 9
+
+// Gauge computes the score of a proposal from its median grade and the amounts of judgments
+// strictly above and strictly below it, out of the total amount of judgments of the proposal.
+type Gauge func(medianGrade uint8, above uint64, below uint64, total uint64) *big.Rat
+
+// UsualGauge is the score of the Usual Judgment: α + (p - q) / (2 × (1 - p - q)),
+// where α is the median grade, p the proportion of judgments above it, and q the proportion below it.
+func UsualGauge(medianGrade uint8, above uint64, below uint64, total uint64) *big.Rat {
+	return addToMedianGrade(medianGrade, above, below, 2*(total-above-below))
+}
+
+// TypicalGauge is the score of the Typical Judgment: α + p - q
+func TypicalGauge(medianGrade uint8, above uint64, below uint64, total uint64) *big.Rat {
+	return addToMedianGrade(medianGrade, above, below, total)
+}
+
+// CentralGauge is the score of the Central Judgment: α + (p - q) / (2 × (p + q))
+func CentralGauge(medianGrade uint8, above uint64, below uint64, total uint64) *big.Rat {
+	return addToMedianGrade(medianGrade, above, below, 2*(above+below))
+}
+
+// addToMedianGrade computes α + (above - below) / denominator, or α when the denominator is zero
+func addToMedianGrade(medianGrade uint8, above uint64, below uint64, denominator uint64) *big.Rat {
+	score := new(big.Rat).SetInt64(int64(medianGrade))
+	if 0 == denominator {
+		return score
+	}
+	difference := new(big.Int).Sub(new(big.Int).SetUint64(above), new(big.Int).SetUint64(below))
+	return score.Add(score, new(big.Rat).SetFrac(difference, new(big.Int).SetUint64(denominator)))
+}
+
+// GaugeJudgment is a deliberator ranking proposals by a numeric score, computed by its Gauge.
+// Proposals whose scores are exactly equal share their rank.
+// It implements judgment.DeliberatorInterface.
+type GaugeJudgment struct {
+	Gauge Gauge
+}
+
+// Deliberate is part of the DeliberatorInterface
+func (g *GaugeJudgment) Deliberate(tally *judgment.PollTally) (*judgment.PollResult, error) {
+	amountOfProposals := len(tally.Proposals)
+	if 0 == amountOfProposals {
+		return &judgment.PollResult{Proposals: []*judgment.ProposalResult{}}, nil
+	}
+
+	amountOfGrades := len(tally.Proposals[0].Tally)
+	amountOfJudges := tally.AmountOfJudges
+	if 0 == amountOfJudges {
+		amountOfJudges = tally.GuessAmountOfJudges()
+	}
+	for proposalIndex, proposalTally := range tally.Proposals {
+		if amountOfGrades != len(proposalTally.Tally) {
+			return nil, fmt.Errorf("mishaped tally: " +
+				"some proposals hold more grades than others ; " +
+				"please provide tallies of the same shape")
+		}
+		if amountOfJudges != proposalTally.CountJudgments() {
+			return nil, fmt.Errorf("unbalanced tally: "+
+				"a proposal (#%d) holds another amount of judgments than there are judges ; "+
+				"use one of the PollTally.Balance() methods first", proposalIndex)
+		}
+	}
+
+	amountOfDigitsForGrade := len(fmt.Sprintf("%d", amountOfGrades-1))
+	scores := make([]*big.Rat, 0, amountOfProposals)
+	proposalsResults := make(judgment.ProposalsResults, 0, amountOfProposals)
+	for proposalIndex, proposalTally := range tally.Proposals {
+		analysis := proposalTally.Analyze()
+		score := g.Gauge(
+			analysis.MedianGrade,
+			analysis.AdhesionGroupSize,
+			analysis.ContestationGroupSize,
+			analysis.TotalSize,
+		)
+		scores = append(scores, score)
+		proposalsResults = append(proposalsResults, &judgment.ProposalResult{
+			Index:    proposalIndex,
+			Score:    formatScore(score, amountOfDigitsForGrade),
+			Analysis: analysis,
+			Tally:    proposalTally,
+		})
+	}
+
+	proposalsResultsSorted := append(judgment.ProposalsResults{}, proposalsResults...)
+	sort.SliceStable(proposalsResultsSorted, func(i, j int) bool {
+		return scores[proposalsResultsSorted[i].Index].Cmp(scores[proposalsResultsSorted[j].Index]) > 0
+	})
+
+	// Rule: Multiple Proposals may have the same Rank in case of perfect equality.
+	for i, proposalResult := range proposalsResultsSorted {
+		proposalResult.Rank = i + 1
+		if i > 0 {
+			previous := proposalsResultsSorted[i-1]
+			if 0 == scores[previous.Index].Cmp(scores[proposalResult.Index]) {
+				proposalResult.Rank = previous.Rank
+			}
+		}
+	}
+
+	return &judgment.PollResult{
+		Proposals:       proposalsResults,
+		ProposalsSorted: proposalsResultsSorted,
+	}, nil
+}
+
+// formatScore writes the score with a fixed amount of digits, so that scores may be compared lexicographically,
+// like the scores of the Majority Judgment.  Scores are never negative, since the median grade outweighs the rest.
+func formatScore(score *big.Rat, amountOfDigitsForGrade int) string {
+	formatted := score.FloatString(scoreDecimals)
+	integerPart := strings.SplitN(formatted, ".", 2)[0]
+	if len(integerPart) < amountOfDigitsForGrade {
+		formatted = strings.Repeat("0", amountOfDigitsForGrade-len(integerPart)) + formatted
+	}
+	return formatted
+}
@@ -0,0 +1,127 @@
+package formatter
+
+import (
+	"fmt"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"html"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// formatProbability formats a probability from 0 to 1 as a percentage, like "52.1%"
+func formatProbability(probability float64) string {
+	return fmt.Sprintf("%.1f%%", 100.0*probability)
+}
+
+// formatGradeInterval formats the confidence interval of the majority grade, like "fair – good",
+// or only "good" when both ends are the same grade
+func formatGradeInterval(proposalBootstrap *analysis.ProposalBootstrap, grades []string) string {
+	low := getGradeName(grades, proposalBootstrap.MajorityGradeLow)
+	if proposalBootstrap.MajorityGradeLow == proposalBootstrap.MajorityGradeHigh {
+		return low
+	}
+	return low + " – " + getGradeName(grades, proposalBootstrap.MajorityGradeHigh)
+}
+
+// describeBootstrap tells how the bootstrap was made, like "Bootstrap of 1000 resampled polls (seed 42)"
+func describeBootstrap(bootstrap *analysis.Bootstrap) string {
+	return fmt.Sprintf("Bootstrap of %d resampled polls (seed %d)", bootstrap.Iterations, bootstrap.Seed)
+}
+
+// describeGradeIntervalTitle is the title of the column of the confidence intervals, like "Majority Grade (95%)"
+func describeGradeIntervalTitle(bootstrap *analysis.Bootstrap) string {
+	return "Majority Grade (" + strconv.FormatFloat(100.0*bootstrap.Confidence, 'f', -1, 64) + "%)"
+}
+
+// makeTextBootstrap makes the table of the bootstrap shown below the text chart,
+// with the probabilities of winning and of each rank, and the confidence interval of the majority grade
+func makeTextBootstrap(
+	bootstrap *analysis.Bootstrap,
+	proposalsResults judgment.ProposalsResults,
+	proposals []string,
+	grades []string,
+	amountOfCharactersForProposal int,
+) string {
+	titles := []string{"Win"}
+	for rank := 1; rank <= len(bootstrap.Proposals); rank++ {
+		titles = append(titles, "#"+strconv.Itoa(rank))
+	}
+	titles = append(titles, describeGradeIntervalTitle(bootstrap))
+
+	rows := make([][]string, 0, len(proposalsResults))
+	for _, proposalResult := range proposalsResults {
+		proposalBootstrap := &bootstrap.Proposals[proposalResult.Index]
+		row := []string{formatProbability(proposalBootstrap.WinProbability)}
+		for _, probability := range proposalBootstrap.RankProbabilities {
+			row = append(row, formatProbability(probability))
+		}
+		row = append(row, formatGradeInterval(proposalBootstrap, grades))
+		rows = append(rows, row)
+	}
+
+	amountOfCharactersForColumns := make([]int, 0, len(titles))
+	for column, title := range titles {
+		amountOfCharacters := measureStringLength(title)
+		for _, row := range rows {
+			if measureStringLength(row[column]) > amountOfCharacters {
+				amountOfCharacters = measureStringLength(row[column])
+			}
+		}
+		amountOfCharactersForColumns = append(amountOfCharactersForColumns, amountOfCharacters)
+	}
+
+	out := describeBootstrap(bootstrap) + ":\n"
+	header := fmt.Sprintf(" %*s ", amountOfCharactersForProposal, "")
+	for column, title := range titles {
+		header += fmt.Sprintf(" %-*s", amountOfCharactersForColumns[column], title)
+	}
+	out += strings.TrimRight(header, " ") + "\n"
+	for i, proposalResult := range proposalsResults {
+		line := fmt.Sprintf(
+			" %*s ",
+			amountOfCharactersForProposal,
+			truncateString(proposals[proposalResult.Index], amountOfCharactersForProposal, '…'),
+		)
+		for column, cell := range rows[i] {
+			line += fmt.Sprintf(" %-*s", amountOfCharactersForColumns[column], cell)
+		}
+		out += strings.TrimRight(line, " ") + "\n"
+	}
+
+	return out
+}
+
+// makeHtmlBootstrap makes the section of the bootstrap, shown below the ranking
+func makeHtmlBootstrap(
+	bootstrap *analysis.Bootstrap,
+	proposalsResults judgment.ProposalsResults,
+	proposals []string,
+	grades []string,
+	palette color.Palette,
+) string {
+	out := "<h2>Bootstrap</h2>\n"
+	out += "<p>" + html.EscapeString(describeBootstrap(bootstrap)) + ".</p>\n"
+	out += "<table class=\"bootstrap\">\n<tr><th>Proposal</th><th>Win</th>"
+	for rank := 1; rank <= len(bootstrap.Proposals); rank++ {
+		out += fmt.Sprintf("<th>#%d</th>", rank)
+	}
+	out += "<th>" + html.EscapeString(describeGradeIntervalTitle(bootstrap)) + "</th></tr>\n"
+	for _, proposalResult := range proposalsResults {
+		proposalBootstrap := &bootstrap.Proposals[proposalResult.Index]
+		out += fmt.Sprintf("<tr><td>%s</td>", html.EscapeString(proposals[proposalResult.Index]))
+		out += fmt.Sprintf("<td class=\"number\">%s</td>", formatProbability(proposalBootstrap.WinProbability))
+		for _, probability := range proposalBootstrap.RankProbabilities {
+			out += fmt.Sprintf("<td class=\"number\">%s</td>", formatProbability(probability))
+		}
+		out += "<td>" + makeHtmlGrade(grades, palette, proposalBootstrap.MajorityGradeLow)
+		if proposalBootstrap.MajorityGradeLow != proposalBootstrap.MajorityGradeHigh {
+			out += " – " + makeHtmlGrade(grades, palette, proposalBootstrap.MajorityGradeHigh)
+		}
+		out += "</td></tr>\n"
+	}
+	out += "</table>\n"
+
+	return out
+}
@@ -3,6 +3,7 @@ package formatter
 import (
 	"bytes"
 	"encoding/csv"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
 	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
 	"log"
 	"strconv"
@@ -11,6 +12,15 @@ import (
 // CsvFormatter formats the results as CSV, with , as delimiter and " as quote
 type CsvFormatter struct{}
 
+func init() {
+	Register(Registration{
+		Name:        "csv",
+		Description: "ranks, scores and majority grades, as CSV",
+		MediaType:   "text/csv; charset=utf-8",
+		Create:      func() Formatter { return &CsvFormatter{} },
+	})
+}
+
 // Format the provided results
 func (t *CsvFormatter) Format(
 	pollTally *judgment.PollTally,
@@ -19,6 +29,7 @@ func (t *CsvFormatter) Format(
 	grades []string,
 	options *Options,
 ) (string, error) {
+	deliberation := getDeliberation(options)
 	proposalsResults := result.Proposals
 	if options.Sorted {
 		proposalsResults = result.ProposalsSorted
@@ -31,13 +42,46 @@ func (t *CsvFormatter) Format(
 		log.Fatal(err)
 	}
 
-	headersWriteErr := writer.Write([]string{
+	var methodColumns []methodColumn
+	if nil != deliberation.Methods {
+		methodColumns = makeMethodColumns(deliberation.Methods, grades)
+	}
+
+	headers := []string{
 		"Rank",
 		"Proposal",
 		"Score",
 		"MajorityGrade",
 		"SecondMajorityGrade",
-	})
+	}
+	for _, column := range methodColumns {
+		headers = append(headers, column.Name, column.Name+"Rank")
+	}
+	if nil != deliberation.Methods && deliberation.Methods.HasBallots {
+		headers = append(headers, "CondorcetWinner")
+	}
+	var proposalsMargins []proposalMargins
+	if nil != deliberation.Margins {
+		proposalsMargins = makeProposalsMargins(deliberation.Margins, len(proposals))
+		headers = append(headers, "LeadOneGrade", "LeadAnyGrade", "ToWinOneGrade", "ToWinAnyGrade")
+	}
+	if nil != deliberation.Bootstrap {
+		headers = append(headers, "WinProbability")
+		for rank := 1; rank <= len(deliberation.Bootstrap.Proposals); rank++ {
+			headers = append(headers, "Rank"+strconv.Itoa(rank)+"Probability")
+		}
+		headers = append(headers, "MajorityGradeLow", "MajorityGradeHigh")
+	}
+	if nil != deliberation.Winners {
+		headers = append(headers, "Elected")
+	}
+	if nil != deliberation.Groups {
+		for _, group := range deliberation.Groups.Groups {
+			headers = append(headers, group.Name+":Rank", group.Name+":MajorityGrade")
+		}
+		headers = append(headers, "HighestRank", "LowestRank", "Divergent")
+	}
+	headersWriteErr := writer.Write(headers)
 
 	if nil != headersWriteErr {
 		log.Fatal(headersWriteErr)
@@ -45,13 +89,69 @@ func (t *CsvFormatter) Format(
 
 	for _, proposalResult := range proposalsResults {
 
-		writeErr := writer.Write([]string{
+		row := []string{
 			strconv.Itoa(proposalResult.Rank),
 			proposals[proposalResult.Index],
 			proposalResult.Score,
 			grades[proposalResult.Analysis.MedianGrade],
 			grades[proposalResult.Analysis.SecondMedianGrade],
-		})
+		}
+		for _, column := range methodColumns {
+			methodsResult := &deliberation.Methods.Proposals[proposalResult.Index]
+			row = append(
+				row,
+				strconv.FormatFloat(column.Value(methodsResult), 'f', -1, 64),
+				strconv.Itoa(column.Rank(methodsResult)),
+			)
+		}
+		if nil != deliberation.Methods && deliberation.Methods.HasBallots {
+			row = append(row, strconv.FormatBool(deliberation.Methods.Proposals[proposalResult.Index].CondorcetWinner))
+		}
+		if nil != proposalsMargins {
+			for _, margin := range []*analysis.Margin{
+				proposalsMargins[proposalResult.Index].Lead,
+				proposalsMargins[proposalResult.Index].ToWin,
+			} {
+				if nil == margin {
+					row = append(row, "", "")
+					continue
+				}
+				row = append(row, formatMarginAmount(margin.OneGradeMoves), formatMarginAmount(margin.AnyGradeMoves))
+			}
+		}
+		if nil != deliberation.Bootstrap {
+			proposalBootstrap := &deliberation.Bootstrap.Proposals[proposalResult.Index]
+			row = append(row, strconv.FormatFloat(proposalBootstrap.WinProbability, 'f', -1, 64))
+			for _, probability := range proposalBootstrap.RankProbabilities {
+				row = append(row, strconv.FormatFloat(probability, 'f', -1, 64))
+			}
+			row = append(
+				row,
+				getGradeName(grades, proposalBootstrap.MajorityGradeLow),
+				getGradeName(grades, proposalBootstrap.MajorityGradeHigh),
+			)
+		}
+		if nil != deliberation.Winners {
+			row = append(row, strconv.FormatBool(isElected(options, proposalResult.Index)))
+		}
+		if nil != deliberation.Groups {
+			for _, group := range deliberation.Groups.Groups {
+				groupResult := group.Round.Result.Proposals[proposalResult.Index]
+				row = append(
+					row,
+					strconv.Itoa(groupResult.Rank),
+					getGradeName(grades, int(groupResult.Analysis.MedianGrade)),
+				)
+			}
+			proposalGroups := &deliberation.Groups.Proposals[proposalResult.Index]
+			row = append(
+				row,
+				strconv.Itoa(proposalGroups.HighestRank),
+				strconv.Itoa(proposalGroups.LowestRank),
+				strconv.FormatBool(proposalGroups.Divergent),
+			)
+		}
+		writeErr := writer.Write(row)
 		if nil != writeErr {
 			log.Fatal(writeErr)
 		}
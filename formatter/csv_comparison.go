@@ -0,0 +1,71 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+	"strconv"
+)
+
+// CsvComparisonFormatter formats the comparison of two rounds of a poll as CSV, one row per proposal
+type CsvComparisonFormatter struct{}
+
+// FormatComparison formats the provided comparison
+func (t *CsvComparisonFormatter) FormatComparison(
+	comparison *analysis.Comparison,
+	options *Options,
+) (string, error) {
+	buffer := new(bytes.Buffer)
+	writer := csv.NewWriter(buffer)
+
+	header := []string{
+		"Proposal",
+		"Status",
+		"RankBefore",
+		"RankAfter",
+		"RankChange",
+		"MajorityGradeBefore",
+		"MajorityGradeAfter",
+		"MajorityGradeChange",
+	}
+	for _, grade := range comparison.Grades {
+		header = append(header, "Delta "+grade)
+	}
+	if headerErr := writer.Write(header); nil != headerErr {
+		return "", headerErr
+	}
+
+	formatRank := func(rank int) string {
+		if 0 == rank {
+			return ""
+		}
+		return strconv.Itoa(rank)
+	}
+
+	for _, proposal := range comparison.Proposals {
+		row := []string{
+			proposal.Name,
+			proposal.Status,
+			formatRank(proposal.RankBefore),
+			formatRank(proposal.RankAfter),
+			strconv.Itoa(proposal.RankChange),
+			getGradeName(comparison.Grades, proposal.MajorityGradeBefore),
+			getGradeName(comparison.Grades, proposal.MajorityGradeAfter),
+			strconv.Itoa(proposal.MajorityGradeChange),
+		}
+		for gradeIndex := range comparison.Grades {
+			delta := ""
+			if nil != proposal.TallyDelta {
+				delta = strconv.FormatFloat(proposal.TallyDelta[gradeIndex], 'f', -1, 64)
+			}
+			row = append(row, delta)
+		}
+		if rowErr := writer.Write(row); nil != rowErr {
+			return "", rowErr
+		}
+	}
+
+	writer.Flush()
+
+	return buffer.String(), writer.Error()
+}
@@ -0,0 +1,47 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+)
+
+// CsvDuelsFormatter formats the duels between all the proposals as a CSV matrix.
+// Each cell holds the winner and the majority grade that decided, like "Chips: good", or "tie: good".
+type CsvDuelsFormatter struct{}
+
+// FormatDuels formats the provided duels
+func (t *CsvDuelsFormatter) FormatDuels(
+	duels *analysis.Duels,
+	options *Options,
+) (string, error) {
+	order := orderDuelingProposals(duels, options.Sorted)
+
+	header := []string{""}
+	for _, i := range order {
+		header = append(header, duels.Proposals[i].Name)
+	}
+	rows := [][]string{header}
+	for _, i := range order {
+		row := []string{duels.Proposals[i].Name}
+		for _, j := range order {
+			duel := duels.Duels[i][j]
+			cell := ""
+			if !duel.IsAgainstItself() {
+				cell = "tie"
+				if duel.Winner >= 0 {
+					cell = duels.Proposals[duel.Winner].Name
+				}
+				cell += ": " + getGradeName(duels.Grades, duel.DecidingGrade)
+			}
+			row = append(row, cell)
+		}
+		rows = append(rows, row)
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := csv.NewWriter(buffer)
+	writer.WriteAll(rows) // flushes
+
+	return buffer.String(), writer.Error()
+}
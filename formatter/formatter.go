@@ -1,22 +1,33 @@
 package formatter
 
-import "github.com/mieuxvoter/majority-judgment-library-go/judgment"
+import (
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+	"github.com/MieuxVoter/majority-judgment-cli/deliberator"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"strconv"
+	"strings"
+)
 
 // Options are shared between all formatters.
 // Some formatters may ignore some options.
 type Options struct {
 	Colorized  bool
-	Scale      float64 // so we can use integers internally, and display floats
+	Scale      float64 // common denominator of the tallies, so we can use integers internally, and display floats
 	Sorted     bool
 	Terminal   string // User-defined gnuplot terminal, only used by gnuplot formatters
 	Width      int
-	GreenToRed bool // horizontal order of the grades in the merit profiles and such
+	GreenToRed bool   // horizontal order of the grades in the merit profiles and such
+	MeritBars  bool   // whether to draw the merit profiles in tables, like in markdown
+	Template   string // path to the user-defined text/template, only used by the template formatter
+	Method     string // deliberation method of the results ; empty means Majority Judgment
+	// What was computed along with the results, like the margins, shown by some formatters ; may be nil
+	Deliberation *analysis.Deliberation
 }
 
 const defaultWidth = 79
 
 // Formatter to implement to make another formatter
-// Keep in mind you need to add it to the "if else if" in root command as well
+// Keep in mind you need to Register it as well, in the init() of its file
 type Formatter interface {
 	// Format the provided results
 	Format(
@@ -28,6 +39,61 @@ type Formatter interface {
 	) (string, error)
 }
 
+// BinaryFormatter is a Formatter whose output is binary data, like an image.
+// Its output is written as is, without a trailing newline, and is not meant for the terminal.
+type BinaryFormatter interface {
+	Formatter
+	IsBinary() bool
+}
+
+// ComparisonFormatter formats the comparison of two rounds of a poll, made by the compare command
+type ComparisonFormatter interface {
+	FormatComparison(comparison *analysis.Comparison, options *Options) (string, error)
+}
+
+// ExplanationFormatter formats the justification of a ranking, made by the explain command
+type ExplanationFormatter interface {
+	FormatExplanation(explanation *analysis.Explanation, options *Options) (string, error)
+}
+
+// DuelsFormatter formats the matrix of the duels between all the proposals, made by the duels command
+type DuelsFormatter interface {
+	FormatDuels(duels *analysis.Duels, options *Options) (string, error)
+}
+
+// getDeliberation returns what was computed along with the results, nothing when the options hold none
+func getDeliberation(options *Options) *analysis.Deliberation {
+	if nil == options.Deliberation {
+		return &analysis.Deliberation{}
+	}
+	return options.Deliberation
+}
+
+// showsScores tells whether the formatters should display the scores of the results.
+// Scores of the Majority Judgment are only meant to be sorted, but the other methods have numeric scores.
+func showsScores(options *Options) bool {
+	return !deliberator.IsMajorityJudgment(options.Method)
+}
+
+// formatScore shortens a numeric score for humans, like 3.125000000 into 3.125
+func formatScore(score string) string {
+	value, parseErr := strconv.ParseFloat(score, 64)
+	if nil != parseErr {
+		return score
+	}
+	return strconv.FormatFloat(value, 'f', 3, 64)
+}
+
+// formatAmount formats an amount of judgments, which may be a float when the input was scaled
+func formatAmount(amount uint64, scale float64) string {
+	if scale == 1.0 {
+		return strconv.FormatUint(amount, 10)
+	}
+	// The scale may not be a power of ten, like with thirds, so we round to a sensible amount of decimals
+	formatted := strconv.FormatFloat(float64(amount)/scale, 'f', 6, 64)
+	return strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
+}
+
 // measureStringLength with support for unicode (hopefully)
 // Heavy-duty replacement for len(str)
 func measureStringLength(str string) int {
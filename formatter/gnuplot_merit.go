@@ -12,6 +12,17 @@ import (
 // GnuplotMeritFormatter creates a script for gnuplot that displays the merit profiles
 type GnuplotMeritFormatter struct{}
 
+func init() {
+	Register(Registration{
+		Name:        "gnuplot",
+		Aliases:     []string{"plot"},
+		Chart:       "merit",
+		Description: "gnuplot script drawing the merit profiles",
+		MediaType:   "text/plain; charset=utf-8",
+		Create:      func() Formatter { return &GnuplotMeritFormatter{} },
+	})
+}
+
 // Format the provided results in the gnuplot script form
 func (t *GnuplotMeritFormatter) Format(
 	pollTally *judgment.PollTally,
@@ -43,7 +54,10 @@ func (t *GnuplotMeritFormatter) Format(
 	for _, proposalResult := range proposalsResults {
 		proposalTally := pollTally.Proposals[proposalResult.Index]
 		row := make([]string, 0, 10)
-		row = append(row, truncateString(proposals[proposalResult.Index], 23, '…'))
+		row = append(row, markElectedProposal(
+			truncateString(proposals[proposalResult.Index], 23, '…'),
+			proposalResult.Index, options, electedMark,
+		))
 
 		for gradeIndex := range grades {
 			row = append(row, strconv.FormatFloat(
@@ -75,7 +89,7 @@ $data <<EOD
 EOD
 set datafile separator ','
 
-set title 'Merit Profiles'
+set title '` + makeTitle("Merit Profiles", options) + `'
 
 set terminal ` + strings.TrimSpace(options.Terminal) + ` \
     size 1024, ` + strconv.Itoa(plotHeight) + ` \
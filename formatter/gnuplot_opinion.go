@@ -12,6 +12,17 @@ import (
 // GnuplotOpinionFormatter creates a script for gnuplot that shows the opinion profile
 type GnuplotOpinionFormatter struct{}
 
+func init() {
+	Register(Registration{
+		Name:        "gnuplot",
+		Aliases:     []string{"plot"},
+		Chart:       "opinion",
+		Description: "gnuplot script drawing the opinion profile",
+		MediaType:   "text/plain; charset=utf-8",
+		Create:      func() Formatter { return &GnuplotOpinionFormatter{} },
+	})
+}
+
 // Format the provided results
 func (t *GnuplotOpinionFormatter) Format(
 	pollTally *judgment.PollTally,
@@ -25,15 +36,12 @@ func (t *GnuplotOpinionFormatter) Format(
 		proposalsResults = result.ProposalsSorted
 	}
 
-	proposalsNames := proposals
-	if options.Sorted {
-		proposalsNames = make([]string, 0, 10)
-		for _, proposalResult := range proposalsResults {
-			proposalsNames = append(proposalsNames, proposals[proposalResult.Index])
-		}
-	}
-	for i, proposalName := range proposalsNames {
-		proposalsNames[i] = truncateString(proposalName, 16, '…')
+	proposalsNames := make([]string, 0, len(proposalsResults))
+	for _, proposalResult := range proposalsResults {
+		proposalsNames = append(proposalsNames, markElectedProposal(
+			truncateString(proposals[proposalResult.Index], 16, '…'),
+			proposalResult.Index, options, electedMark,
+		))
 	}
 
 	buffer := new(bytes.Buffer)
@@ -85,7 +93,7 @@ $tally << EOD
 EOD
 set datafile separator ','
 
-set title 'Opinion Profile'
+set title '` + makeTitle("Opinion Profile", options) + `'
 
 set terminal ` + strings.TrimSpace(options.Terminal) + ` \
     size ` + strconv.Itoa(plotWidth) + `, 600 \
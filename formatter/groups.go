@@ -0,0 +1,204 @@
+package formatter
+
+import (
+	"fmt"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"html"
+	"image/color"
+	"strings"
+)
+
+// Marks the proposals whose rank differs strongly between the groups
+const divergenceMark = "!"
+
+// describeGroups tells how the judges were grouped, like "Results by department"
+func describeGroups(groups *analysis.Groups) string {
+	return "Results by " + groups.Column
+}
+
+// describeDivergence explains the divergence mark, like "! The rank differs by 2 or more between the groups."
+func describeDivergence(groups *analysis.Groups) string {
+	return fmt.Sprintf(
+		"%s The rank differs by %d or more between the groups.",
+		divergenceMark, groups.DivergenceThreshold,
+	)
+}
+
+// describeGroup names a group along with its amount of judges, like "Sales (12)"
+func describeGroup(group *analysis.Group) string {
+	return fmt.Sprintf("%s (%d)", group.Name, group.AmountOfJudges)
+}
+
+// formatGroupResult formats the rank and the majority grade of a proposal, like "#2 good"
+func formatGroupResult(proposalResult *judgment.ProposalResult, grades []string) string {
+	return fmt.Sprintf("#%d %s", proposalResult.Rank, getGradeName(grades, int(proposalResult.Analysis.MedianGrade)))
+}
+
+// makeTextGroups makes the table of the results of each group, shown below the text chart,
+// with the overall results first
+func makeTextGroups(
+	groups *analysis.Groups,
+	result *judgment.PollResult,
+	proposalsResults judgment.ProposalsResults,
+	proposals []string,
+	grades []string,
+	amountOfCharactersForProposal int,
+	expectedWidth int,
+) string {
+	titles := []string{"Overall"}
+	for i := range groups.Groups {
+		titles = append(titles, describeGroup(&groups.Groups[i]))
+	}
+
+	rows := make([][]string, 0, len(proposalsResults))
+	for _, proposalResult := range proposalsResults {
+		row := []string{formatGroupResult(result.Proposals[proposalResult.Index], grades)}
+		for _, group := range groups.Groups {
+			row = append(row, formatGroupResult(group.Round.Result.Proposals[proposalResult.Index], grades))
+		}
+		rows = append(rows, row)
+	}
+
+	amountOfCharactersForColumns := make([]int, 0, len(titles))
+	for column, title := range titles {
+		amountOfCharacters := measureStringLength(title)
+		for _, row := range rows {
+			if measureStringLength(row[column]) > amountOfCharacters {
+				amountOfCharacters = measureStringLength(row[column])
+			}
+		}
+		amountOfCharactersForColumns = append(amountOfCharactersForColumns, amountOfCharacters)
+	}
+
+	hasDivergence := false
+	out := describeGroups(groups) + ":\n"
+	header := fmt.Sprintf("   %*s ", amountOfCharactersForProposal, "")
+	for column, title := range titles {
+		header += fmt.Sprintf(" %-*s", amountOfCharactersForColumns[column], title)
+	}
+	out += strings.TrimRight(header, " ") + "\n"
+	for i, proposalResult := range proposalsResults {
+		mark := " "
+		if groups.Proposals[proposalResult.Index].Divergent {
+			mark = divergenceMark
+			hasDivergence = true
+		}
+		line := fmt.Sprintf(
+			" %s %*s ",
+			mark,
+			amountOfCharactersForProposal,
+			truncateString(proposals[proposalResult.Index], amountOfCharactersForProposal, '…'),
+		)
+		for column, cell := range rows[i] {
+			line += fmt.Sprintf(" %-*s", amountOfCharactersForColumns[column], cell)
+		}
+		out += strings.TrimRight(line, " ") + "\n"
+	}
+	if hasDivergence {
+		out += wrapText(describeDivergence(groups), expectedWidth, "") + "\n"
+	}
+
+	return out
+}
+
+// makeHtmlGroups makes the section of the results of each group, shown below the ranking
+func makeHtmlGroups(
+	groups *analysis.Groups,
+	result *judgment.PollResult,
+	proposalsResults judgment.ProposalsResults,
+	proposals []string,
+	grades []string,
+	palette color.Palette,
+) string {
+	out := "<h2>" + html.EscapeString(describeGroups(groups)) + "</h2>\n"
+	out += "<table class=\"groups\">\n<tr><th>Proposal</th><th>Overall</th>"
+	for i := range groups.Groups {
+		out += "<th>" + html.EscapeString(describeGroup(&groups.Groups[i])) + "</th>"
+	}
+	out += "</tr>\n"
+
+	makeCell := func(proposalResult *judgment.ProposalResult) string {
+		return fmt.Sprintf(
+			"<td>#%d %s</td>",
+			proposalResult.Rank,
+			makeHtmlGrade(grades, palette, int(proposalResult.Analysis.MedianGrade)),
+		)
+	}
+	hasDivergence := false
+	for _, proposalResult := range proposalsResults {
+		name := html.EscapeString(proposals[proposalResult.Index])
+		if groups.Proposals[proposalResult.Index].Divergent {
+			out += "<tr class=\"divergent\"><td>" + divergenceMark + " " + name + "</td>"
+			hasDivergence = true
+		} else {
+			out += "<tr><td>" + name + "</td>"
+		}
+		out += makeCell(result.Proposals[proposalResult.Index])
+		for _, group := range groups.Groups {
+			out += makeCell(group.Round.Result.Proposals[proposalResult.Index])
+		}
+		out += "</tr>\n"
+	}
+	out += "</table>\n"
+	if hasDivergence {
+		out += "<p>" + html.EscapeString(describeDivergence(groups)) + "</p>\n"
+	}
+
+	return out
+}
+
+// jsonGroups holds the results of each group of judges, deliberated apart
+type jsonGroups struct {
+	Column              string              `json:"column"`
+	DivergenceThreshold int                 `json:"divergenceThreshold"`
+	Groups              []jsonGroup         `json:"groups"`
+	Proposals           []jsonProposalGroup `json:"proposals"` // in the order of the proposals
+}
+
+type jsonGroup struct {
+	Name           string               `json:"name"`
+	AmountOfJudges int                  `json:"amountOfJudges"` // regardless of their weights
+	Tally          *judgment.PollTally  `json:"tally"`
+	Scale          float64              `json:"scale,omitempty"` // the tally was multiplied by it, only when not 1
+	Result         *judgment.PollResult `json:"result"`
+}
+
+type jsonProposalGroup struct {
+	HighestRank int  `json:"highestRank"`
+	LowestRank  int  `json:"lowestRank"`
+	Divergent   bool `json:"divergent"`
+}
+
+// makeJsonGroups prepares the results of the groups for JSON, or nil when there are none
+func makeJsonGroups(groups *analysis.Groups) *jsonGroups {
+	if nil == groups {
+		return nil
+	}
+
+	jsonGroupsList := make([]jsonGroup, 0, len(groups.Groups))
+	for _, group := range groups.Groups {
+		jsonGroupsList = append(jsonGroupsList, jsonGroup{
+			Name:           group.Name,
+			AmountOfJudges: group.AmountOfJudges,
+			Tally:          group.Round.Tally,
+			Scale:          makeDocumentScale(group.Round.Scale),
+			Result:         group.Round.Result,
+		})
+	}
+	proposals := make([]jsonProposalGroup, 0, len(groups.Proposals))
+	for _, proposalGroups := range groups.Proposals {
+		proposals = append(proposals, jsonProposalGroup{
+			HighestRank: proposalGroups.HighestRank,
+			LowestRank:  proposalGroups.LowestRank,
+			Divergent:   proposalGroups.Divergent,
+		})
+	}
+
+	return &jsonGroups{
+		Column:              groups.Column,
+		DivergenceThreshold: groups.DivergenceThreshold,
+		Groups:              jsonGroupsList,
+		Proposals:           proposals,
+	}
+}
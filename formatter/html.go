@@ -0,0 +1,200 @@
+package formatter
+
+import (
+	"fmt"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"html"
+	"image/color"
+)
+
+// Size of the merit profiles drawn in the ranking table, in pixels
+const htmlMeritProfileWidth = 400
+const htmlMeritProfileHeight = 20
+
+// HtmlFormatter creates a self-contained HTML document, without external assets,
+// so that it may be published, emailed or archived as is.
+// It holds the ranking, the merit profiles, the tally and the legend of the grades.
+type HtmlFormatter struct{}
+
+func init() {
+	Register(Registration{
+		Name:        "html",
+		Description: "self-contained report with the ranking, merit profiles and tally",
+		MediaType:   "text/html; charset=utf-8",
+		Create:      func() Formatter { return &HtmlFormatter{} },
+	})
+}
+
+// Format the provided results
+func (t *HtmlFormatter) Format(
+	pollTally *judgment.PollTally,
+	result *judgment.PollResult,
+	proposals []string,
+	grades []string,
+	options *Options,
+) (string, error) {
+	deliberation := getDeliberation(options)
+	winnersNote := makeWinnersNote(options, proposals, electedMark)
+	proposals = markElectedProposals(proposals, options, electedMark)
+	proposalsResults := result.Proposals
+	if options.Sorted {
+		proposalsResults = result.ProposalsSorted
+	}
+
+	palette := judgment.CreateDefaultPalette(len(grades))
+
+	gradesIndices := make([]int, 0, len(grades))
+	for i := range grades {
+		gradeIndex := i
+		if options.GreenToRed {
+			gradeIndex = len(grades) - 1 - i
+		}
+		gradesIndices = append(gradesIndices, gradeIndex)
+	}
+
+	out := `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Majority Judgment Results</title>
+<style>
+body { font-family: sans-serif; background: ` + svgBackgroundColor + `; color: ` + svgTextColor + `; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { padding: 0.3em 0.8em; border-bottom: 1px solid #cccccc; text-align: left; }
+td.number { text-align: right; font-variant-numeric: tabular-nums; }
+td.profile { padding: 0.1em 0.8em; }
+.swatch { display: inline-block; width: 1em; height: 1em; vertical-align: middle; margin-right: 0.3em; }
+.legend span.grade { margin-right: 1.5em; white-space: nowrap; }
+tr.divergent td { background: #fff3cd; }
+tr.elected td { font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>Majority Judgment Results</h1>
+`
+	if isWeighted(options) {
+		out += "<p class=\"weighted\">" + html.EscapeString(weightedNote) + "</p>\n"
+	}
+
+	// I. Ranking, with the merit profiles
+	out += "<h2>Ranking</h2>\n<table class=\"ranking\">\n<tr>"
+	var methodColumns []methodColumn
+	if nil != deliberation.Methods {
+		methodColumns = makeMethodColumns(deliberation.Methods, grades)
+	}
+	out += "<th>Rank</th>"
+	for _, column := range methodColumns {
+		out += fmt.Sprintf("<th>%s</th>", html.EscapeString(column.Title))
+	}
+	out += "<th>Proposal</th><th>Majority Grade</th><th>Second Majority Grade</th>"
+	out += "<th>Merit Profile</th><th>Score</th></tr>\n"
+	for _, proposalResult := range proposalsResults {
+		if isElected(options, proposalResult.Index) {
+			out += "<tr class=\"elected\">"
+		} else {
+			out += "<tr>"
+		}
+		out += fmt.Sprintf("<td class=\"number\">#%d</td>", proposalResult.Rank)
+		for _, column := range methodColumns {
+			out += fmt.Sprintf(
+				"<td class=\"number\">%s</td>",
+				html.EscapeString(formatMethodCell(&column, &deliberation.Methods.Proposals[proposalResult.Index])),
+			)
+		}
+		out += fmt.Sprintf("<td>%s</td>", html.EscapeString(proposals[proposalResult.Index]))
+		out += fmt.Sprintf("<td>%s</td>", makeHtmlGrade(grades, palette, int(proposalResult.Analysis.MedianGrade)))
+		out += fmt.Sprintf("<td>%s</td>", makeHtmlGrade(grades, palette, int(proposalResult.Analysis.SecondMedianGrade)))
+		out += "<td class=\"profile\">" + makeHtmlMeritProfile(
+			pollTally.Proposals[proposalResult.Index],
+			grades,
+			palette,
+			options.GreenToRed,
+		) + "</td>"
+		out += fmt.Sprintf("<td><code>%s</code></td>", html.EscapeString(proposalResult.Score))
+		out += "</tr>\n"
+	}
+	out += "</table>\n"
+	if "" != winnersNote {
+		out += "<p class=\"winners\">" + html.EscapeString(winnersNote) + "</p>\n"
+	}
+
+	if nil != deliberation.Bootstrap {
+		out += makeHtmlBootstrap(deliberation.Bootstrap, proposalsResults, proposals, grades, palette)
+	}
+
+	if nil != deliberation.Groups {
+		out += makeHtmlGroups(deliberation.Groups, result, proposalsResults, proposals, grades, palette)
+	}
+
+	// II. Raw tally
+	out += "<h2>Tally</h2>\n<table class=\"tally\">\n<tr><th>Proposal</th>"
+	for _, gradeIndex := range gradesIndices {
+		out += fmt.Sprintf("<th>%s</th>", makeHtmlGrade(grades, palette, gradeIndex))
+	}
+	out += "<th>Total</th></tr>\n"
+	for _, proposalResult := range proposalsResults {
+		proposalTally := pollTally.Proposals[proposalResult.Index]
+		out += fmt.Sprintf("<tr><td>%s</td>", html.EscapeString(proposals[proposalResult.Index]))
+		for _, gradeIndex := range gradesIndices {
+			out += fmt.Sprintf(
+				"<td class=\"number\">%s</td>",
+				formatAmount(proposalTally.Tally[gradeIndex], options.Scale),
+			)
+		}
+		out += fmt.Sprintf(
+			"<td class=\"number\">%s</td></tr>\n",
+			formatAmount(proposalTally.CountJudgments(), options.Scale),
+		)
+	}
+	out += "</table>\n"
+
+	// III. Legend of the grades
+	out += "<h2>Grades</h2>\n<p class=\"legend\">\n"
+	for _, gradeIndex := range gradesIndices {
+		out += "<span class=\"grade\">" + makeHtmlGrade(grades, palette, gradeIndex) + "</span>\n"
+	}
+	out += "</p>\n"
+
+	out += "</body>\n</html>"
+
+	return out, nil
+}
+
+// makeHtmlGrade writes the grade's name along with a swatch of its color
+func makeHtmlGrade(grades []string, palette color.Palette, gradeIndex int) string {
+	return fmt.Sprintf(
+		"<span class=\"swatch\" style=\"background: %s\"></span>%s",
+		judgment.DumpColorHexString(palette[gradeIndex], "#", false),
+		html.EscapeString(grades[gradeIndex]),
+	)
+}
+
+// makeHtmlMeritProfile draws the merit profile as an inline SVG
+func makeHtmlMeritProfile(
+	tally *judgment.ProposalTally,
+	grades []string,
+	palette color.Palette,
+	greenToRed bool,
+) string {
+	svg := fmt.Sprintf(
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		htmlMeritProfileWidth, htmlMeritProfileHeight, htmlMeritProfileWidth, htmlMeritProfileHeight,
+	) + "\n"
+	svg += makeSvgMeritProfile(
+		tally,
+		grades,
+		0,
+		0,
+		htmlMeritProfileWidth,
+		htmlMeritProfileHeight,
+		palette,
+		greenToRed,
+	)
+	svg += fmt.Sprintf(
+		`<line x1="%d" y1="0" x2="%d" y2="%d" stroke="%s" stroke-width="2" stroke-dasharray="4,2"/>`,
+		htmlMeritProfileWidth/2, htmlMeritProfileWidth/2, htmlMeritProfileHeight, svgTextColor,
+	) + "\n"
+	svg += "</svg>"
+
+	return svg
+}
@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"encoding/json"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
 	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
 )
 
@@ -9,6 +10,15 @@ import (
 // All on one line, feel free to make an option if you can figure out how to beautify it
 type JsonFormatter struct{}
 
+func init() {
+	Register(Registration{
+		Name:        "json",
+		Description: "proposals, grades, tally and results, as JSON",
+		MediaType:   "application/json",
+		Create:      func() Formatter { return &JsonFormatter{} },
+	})
+}
+
 // Format the provided results
 func (t *JsonFormatter) Format(
 	tally *judgment.PollTally,
@@ -17,19 +27,45 @@ func (t *JsonFormatter) Format(
 	grades []string,
 	options *Options,
 ) (string, error) {
+	deliberation := getDeliberation(options)
 
 	// JSON can ignore options.Sorted because it always sends back everything
 
+	method := ""
+	if showsScores(options) {
+		method = options.Method
+	}
+
 	jsonBytes, jsonErr := json.Marshal(struct {
 		Proposals []string             `json:"proposals"`
 		Grades    []string             `json:"grades"`
 		Tally     *judgment.PollTally  `json:"tally"`
+		Scale     float64              `json:"scale,omitempty"` // the tally was multiplied by it, only when not 1
 		Result    *judgment.PollResult `json:"result"`
+		Method    string               `json:"method,omitempty"` // only when not Majority Judgment
+		Methods   *jsonMethods         `json:"methods,omitempty"`
+		Bootstrap *jsonBootstrap       `json:"bootstrap,omitempty"`
+		Margins   *jsonMargins         `json:"margins,omitempty"`
+		Weighted  bool                 `json:"weighted,omitempty"`
+		Groups    *jsonGroups          `json:"groups,omitempty"`
+		Winners   *jsonWinners         `json:"winners,omitempty"`
+		// Tally of the judgments counted once each, only when the judges were weighted
+		UnweightedTally *judgment.PollTally `json:"unweightedTally,omitempty"`
 	}{
 		Proposals: proposals,
 		Grades:    grades,
 		Tally:     tally,
+		Scale:     makeDocumentScale(options.Scale),
 		Result:    result,
+		Method:    method,
+		Methods:   makeJsonMethods(deliberation.Methods, grades),
+		Bootstrap: makeJsonBootstrap(deliberation.Bootstrap, grades),
+		Margins:   makeJsonMargins(deliberation.Margins),
+		Weighted:  isWeighted(options),
+		Groups:    makeJsonGroups(deliberation.Groups),
+		Winners:   makeJsonWinners(deliberation.Winners),
+
+		UnweightedTally: deliberation.UnweightedTally,
 	})
 
 	if jsonErr != nil {
@@ -38,3 +74,139 @@ func (t *JsonFormatter) Format(
 
 	return string(jsonBytes), nil
 }
+
+// makeDocumentScale is the scale of the tallies in the JSON and YAML documents, or 0 to omit it when they are unscaled.
+// The document readers divide the tallies by it.
+func makeDocumentScale(scale float64) float64 {
+	if 1 == scale {
+		return 0
+	}
+	return scale
+}
+
+// jsonMethods holds the results of other voting methods, in the order of the proposals
+type jsonMethods struct {
+	ApprovalThreshold string              `json:"approvalThreshold"`
+	Proposals         []jsonMethodsResult `json:"proposals"`
+}
+
+type jsonMethodsResult struct {
+	MeanGrade       float64  `json:"meanGrade"`
+	MeanRank        int      `json:"meanRank"`
+	Approval        float64  `json:"approval"`
+	ApprovalRank    int      `json:"approvalRank"`
+	Borda           float64  `json:"borda"`
+	BordaRank       int      `json:"bordaRank"`
+	Copeland        *float64 `json:"copeland,omitempty"` // only with ballots
+	CopelandRank    int      `json:"copelandRank,omitempty"`
+	CondorcetWinner *bool    `json:"condorcetWinner,omitempty"`
+}
+
+// makeJsonMethods prepares the results of the other voting methods for JSON, or nil when there are none
+func makeJsonMethods(methods *analysis.MethodsComparison, grades []string) *jsonMethods {
+	if nil == methods {
+		return nil
+	}
+
+	results := make([]jsonMethodsResult, 0, len(methods.Proposals))
+	for i := range methods.Proposals {
+		result := &methods.Proposals[i]
+		jsonResult := jsonMethodsResult{
+			MeanGrade:    result.MeanGrade,
+			MeanRank:     result.MeanRank,
+			Approval:     result.Approval,
+			ApprovalRank: result.ApprovalRank,
+			Borda:        result.Borda,
+			BordaRank:    result.BordaRank,
+		}
+		if methods.HasBallots {
+			jsonResult.Copeland = &result.Copeland
+			jsonResult.CopelandRank = result.CopelandRank
+			jsonResult.CondorcetWinner = &result.CondorcetWinner
+		}
+		results = append(results, jsonResult)
+	}
+
+	return &jsonMethods{
+		ApprovalThreshold: getGradeName(grades, methods.ApprovalThreshold),
+		Proposals:         results,
+	}
+}
+
+// jsonBootstrap holds the probabilities from the resampled polls, in the order of the proposals
+type jsonBootstrap struct {
+	Iterations int                     `json:"iterations"`
+	Seed       int64                   `json:"seed"`
+	Confidence float64                 `json:"confidence"`
+	Proposals  []jsonProposalBootstrap `json:"proposals"`
+}
+
+type jsonProposalBootstrap struct {
+	WinProbability    float64   `json:"winProbability"`
+	RankProbabilities []float64 `json:"rankProbabilities"` // from rank 1
+	MajorityGradeLow  string    `json:"majorityGradeLow"`
+	MajorityGradeHigh string    `json:"majorityGradeHigh"`
+}
+
+// makeJsonBootstrap prepares the bootstrap for JSON, or nil when there is none
+func makeJsonBootstrap(bootstrap *analysis.Bootstrap, grades []string) *jsonBootstrap {
+	if nil == bootstrap {
+		return nil
+	}
+
+	proposals := make([]jsonProposalBootstrap, 0, len(bootstrap.Proposals))
+	for _, proposalBootstrap := range bootstrap.Proposals {
+		proposals = append(proposals, jsonProposalBootstrap{
+			WinProbability:    proposalBootstrap.WinProbability,
+			RankProbabilities: proposalBootstrap.RankProbabilities,
+			MajorityGradeLow:  getGradeName(grades, proposalBootstrap.MajorityGradeLow),
+			MajorityGradeHigh: getGradeName(grades, proposalBootstrap.MajorityGradeHigh),
+		})
+	}
+
+	return &jsonBootstrap{
+		Iterations: bootstrap.Iterations,
+		Seed:       bootstrap.Seed,
+		Confidence: bootstrap.Confidence,
+		Proposals:  proposals,
+	}
+}
+
+// jsonMargins holds the margins of the winner against each other proposal,
+// and of each proposal against the next one in the ranking
+type jsonMargins struct {
+	Winner   []jsonMargin `json:"winner"`
+	Adjacent []jsonMargin `json:"adjacent"`
+}
+
+type jsonMargin struct {
+	Above         int      `json:"above"` // index of the proposal
+	Below         int      `json:"below"`
+	OneGradeMoves *float64 `json:"oneGradeMoves"` // null when no change could swap the proposals
+	AnyGradeMoves *float64 `json:"anyGradeMoves"`
+}
+
+// makeJsonMargins prepares the margins for JSON, or nil when there are none
+func makeJsonMargins(margins *analysis.Margins) *jsonMargins {
+	if nil == margins {
+		return nil
+	}
+
+	convert := func(margins []analysis.Margin) []jsonMargin {
+		converted := make([]jsonMargin, 0, len(margins))
+		for i := range margins {
+			margin := jsonMargin{Above: margins[i].Above, Below: margins[i].Below}
+			if margins[i].AnyGradeMoves >= 0 {
+				margin.OneGradeMoves = &margins[i].OneGradeMoves
+				margin.AnyGradeMoves = &margins[i].AnyGradeMoves
+			}
+			converted = append(converted, margin)
+		}
+		return converted
+	}
+
+	return &jsonMargins{
+		Winner:   convert(margins.Winner),
+		Adjacent: convert(margins.Adjacent),
+	}
+}
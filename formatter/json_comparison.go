@@ -0,0 +1,71 @@
+package formatter
+
+import (
+	"encoding/json"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+)
+
+// JsonComparisonFormatter formats the comparison of two rounds of a poll as JSON
+type JsonComparisonFormatter struct{}
+
+// FormatComparison formats the provided comparison
+func (t *JsonComparisonFormatter) FormatComparison(
+	comparison *analysis.Comparison,
+	options *Options,
+) (string, error) {
+	type proposalComparison struct {
+		Name                string    `json:"name"`
+		Status              string    `json:"status"`
+		RankBefore          int       `json:"rankBefore,omitempty"`
+		RankAfter           int       `json:"rankAfter,omitempty"`
+		RankChange          int       `json:"rankChange"`
+		MajorityGradeBefore string    `json:"majorityGradeBefore,omitempty"`
+		MajorityGradeAfter  string    `json:"majorityGradeAfter,omitempty"`
+		MajorityGradeChange int       `json:"majorityGradeChange"`
+		TallyBefore         []float64 `json:"tallyBefore,omitempty"`
+		TallyAfter          []float64 `json:"tallyAfter,omitempty"`
+		TallyDelta          []float64 `json:"tallyDelta,omitempty"`
+	}
+
+	proposals := make([]proposalComparison, 0, len(comparison.Proposals))
+	for _, proposal := range comparison.Proposals {
+		proposals = append(proposals, proposalComparison{
+			Name:                proposal.Name,
+			Status:              proposal.Status,
+			RankBefore:          proposal.RankBefore,
+			RankAfter:           proposal.RankAfter,
+			RankChange:          proposal.RankChange,
+			MajorityGradeBefore: getGradeName(comparison.Grades, proposal.MajorityGradeBefore),
+			MajorityGradeAfter:  getGradeName(comparison.Grades, proposal.MajorityGradeAfter),
+			MajorityGradeChange: proposal.MajorityGradeChange,
+			TallyBefore:         proposal.TallyBefore,
+			TallyAfter:          proposal.TallyAfter,
+			TallyDelta:          proposal.TallyDelta,
+		})
+	}
+
+	jsonBytes, jsonErr := json.Marshal(struct {
+		Grades               []string             `json:"grades"`
+		AmountOfJudgesBefore float64              `json:"amountOfJudgesBefore"`
+		AmountOfJudgesAfter  float64              `json:"amountOfJudgesAfter"`
+		Proposals            []proposalComparison `json:"proposals"`
+	}{
+		Grades:               comparison.Grades,
+		AmountOfJudgesBefore: comparison.AmountOfJudgesBefore,
+		AmountOfJudgesAfter:  comparison.AmountOfJudgesAfter,
+		Proposals:            proposals,
+	})
+	if jsonErr != nil {
+		return "", jsonErr
+	}
+
+	return string(jsonBytes), nil
+}
+
+// getGradeName returns the name of the grade, or an empty string for -1 and other unknown grades
+func getGradeName(grades []string, gradeIndex int) string {
+	if gradeIndex < 0 || gradeIndex >= len(grades) {
+		return ""
+	}
+	return grades[gradeIndex]
+}
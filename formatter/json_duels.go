@@ -0,0 +1,74 @@
+package formatter
+
+import (
+	"encoding/json"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+)
+
+// JsonDuelsFormatter formats the duels between all the proposals as JSON.
+// The duels are a matrix in the order of the proposals, whose diagonal is null.
+type JsonDuelsFormatter struct{}
+
+// FormatDuels formats the provided duels
+func (t *JsonDuelsFormatter) FormatDuels(
+	duels *analysis.Duels,
+	options *Options,
+) (string, error) {
+	type duelingProposal struct {
+		Index int    `json:"index"`
+		Name  string `json:"name"`
+		Rank  int    `json:"rank"`
+	}
+	type duel struct {
+		Winner        *int   `json:"winner"` // index of the winning proposal, null on a tie
+		DecidedBy     string `json:"decidedBy"`
+		DecidingGrade string `json:"decidingGrade"`
+		Step          int    `json:"step"`
+	}
+
+	order := orderDuelingProposals(duels, options.Sorted)
+	proposals := make([]duelingProposal, 0, len(order))
+	matrix := make([][]*duel, 0, len(order))
+	for _, i := range order {
+		proposals = append(proposals, duelingProposal{
+			Index: duels.Proposals[i].Index,
+			Name:  duels.Proposals[i].Name,
+			Rank:  duels.Proposals[i].Rank,
+		})
+		row := make([]*duel, 0, len(order))
+		for _, j := range order {
+			current := duels.Duels[i][j]
+			if current.IsAgainstItself() {
+				row = append(row, nil)
+				continue
+			}
+			var winner *int
+			if current.Winner >= 0 {
+				winnerIndex := duels.Proposals[current.Winner].Index
+				winner = &winnerIndex
+			}
+			row = append(row, &duel{
+				Winner:        winner,
+				DecidedBy:     current.DecidedBy,
+				DecidingGrade: getGradeName(duels.Grades, current.DecidingGrade),
+				Step:          current.Step,
+			})
+		}
+		matrix = append(matrix, row)
+	}
+
+	jsonBytes, jsonErr := json.Marshal(struct {
+		Grades    []string          `json:"grades"`
+		Proposals []duelingProposal `json:"proposals"`
+		Duels     [][]*duel         `json:"duels"`
+	}{
+		Grades:    duels.Grades,
+		Proposals: proposals,
+		Duels:     matrix,
+	})
+	if jsonErr != nil {
+		return "", jsonErr
+	}
+
+	return string(jsonBytes), nil
+}
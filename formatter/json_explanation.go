@@ -0,0 +1,93 @@
+package formatter
+
+import (
+	"encoding/json"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+	"strings"
+)
+
+// JsonExplanationFormatter formats the justification of a ranking as JSON, with the sentences of the text format
+type JsonExplanationFormatter struct{}
+
+// FormatExplanation formats the provided explanation
+func (t *JsonExplanationFormatter) FormatExplanation(
+	explanation *analysis.Explanation,
+	options *Options,
+) (string, error) {
+	type explainedProposal struct {
+		Index         int    `json:"index"`
+		Name          string `json:"name"`
+		Rank          int    `json:"rank"`
+		MajorityGrade string `json:"majorityGrade"`
+	}
+	type stepAnalysis struct {
+		MajorityGrade      string  `json:"majorityGrade"`
+		SecondGroupGrade   string  `json:"secondGroupGrade"`
+		SecondGroupSize    float64 `json:"secondGroupSize"`
+		SecondGroupSign    int     `json:"secondGroupSign"`
+		SecondGroupPercent float64 `json:"secondGroupPercent"`
+	}
+	type tieBreakingStep struct {
+		Above stepAnalysis `json:"above"`
+		Below stepAnalysis `json:"below"`
+	}
+	type decision struct {
+		Above       explainedProposal `json:"above"`
+		Below       explainedProposal `json:"below"`
+		DecidedBy   string            `json:"decidedBy"`
+		Steps       []tieBreakingStep `json:"steps"`
+		Explanation string            `json:"explanation"`
+	}
+
+	grades := explanation.Grades
+	makeProposal := func(proposal analysis.ExplainedProposal) explainedProposal {
+		return explainedProposal{
+			Index:         proposal.Index,
+			Name:          proposal.Name,
+			Rank:          proposal.Rank,
+			MajorityGrade: getGradeName(grades, proposal.MajorityGrade),
+		}
+	}
+	makeStep := func(step analysis.StepAnalysis) stepAnalysis {
+		return stepAnalysis{
+			MajorityGrade:      getGradeName(grades, step.MajorityGrade),
+			SecondGroupGrade:   getGradeName(grades, step.SecondGroupGrade),
+			SecondGroupSize:    step.SecondGroupSize,
+			SecondGroupSign:    step.SecondGroupSign,
+			SecondGroupPercent: step.SecondGroupPercent,
+		}
+	}
+
+	decisions := make([]decision, 0, len(explanation.Decisions))
+	for _, explained := range explanation.Decisions {
+		steps := make([]tieBreakingStep, 0, len(explained.Steps))
+		for _, step := range explained.Steps {
+			steps = append(steps, tieBreakingStep{
+				Above: makeStep(step.First),
+				Below: makeStep(step.Second),
+			})
+		}
+		decisions = append(decisions, decision{
+			Above:       makeProposal(explained.Above),
+			Below:       makeProposal(explained.Below),
+			DecidedBy:   explained.DecidedBy,
+			Steps:       steps,
+			Explanation: strings.Join(makeExplanationSentences(&explained, grades), " "),
+		})
+	}
+
+	jsonBytes, jsonErr := json.Marshal(struct {
+		Grades         []string   `json:"grades"`
+		AmountOfJudges float64    `json:"amountOfJudges"`
+		Decisions      []decision `json:"decisions"`
+	}{
+		Grades:         grades,
+		AmountOfJudges: explanation.AmountOfJudges,
+		Decisions:      decisions,
+	})
+	if jsonErr != nil {
+		return "", jsonErr
+	}
+
+	return string(jsonBytes), nil
+}
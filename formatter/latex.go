@@ -0,0 +1,170 @@
+package formatter
+
+import (
+	"fmt"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"strconv"
+	"strings"
+)
+
+// LatexFormatter creates a LaTeX fragment meant to be \input into a paper.
+// It holds a tabular of the ranking and a PGFPlots chart of the merit profiles,
+// laid out like the GnuplotMeritFormatter does it.
+// The document's preamble requires \usepackage{pgfplots} and \usepackage{xcolor}.
+type LatexFormatter struct{}
+
+func init() {
+	Register(Registration{
+		Name:        "latex",
+		Aliases:     []string{"tex"},
+		Chart:       "merit",
+		Description: "tabular ranking and PGFPlots merit profiles, for papers",
+		MediaType:   "application/x-latex",
+		Create:      func() Formatter { return &LatexFormatter{} },
+	})
+}
+
+// Format the provided results
+func (t *LatexFormatter) Format(
+	pollTally *judgment.PollTally,
+	result *judgment.PollResult,
+	proposals []string,
+	grades []string,
+	options *Options,
+) (string, error) {
+	proposalsResults := result.Proposals
+	if options.Sorted {
+		proposalsResults = result.ProposalsSorted
+	}
+
+	palette := judgment.CreateDefaultPalette(len(grades))
+
+	gradesIndices := make([]int, 0, len(grades))
+	for i := range grades {
+		gradeIndex := i
+		if options.GreenToRed {
+			gradeIndex = len(grades) - 1 - i
+		}
+		gradesIndices = append(gradesIndices, gradeIndex)
+	}
+
+	out := "% Majority Judgment results, generated by mj\n"
+	out += "% Requires \\usepackage{pgfplots} and \\usepackage{xcolor} in the preamble.\n"
+	for gradeIndex := range grades {
+		out += fmt.Sprintf(
+			"\\definecolor{mjGrade%d}{HTML}{%s}\n",
+			gradeIndex,
+			strings.ToUpper(judgment.DumpColorHexString(palette[gradeIndex], "", false)),
+		)
+	}
+	out += "\n"
+
+	// I. Ranking
+	if showsScores(options) {
+		out += "\\begin{tabular}{rllr}\n\\hline\n"
+		out += "Rank & Proposal & Majority Grade & Score \\\\\n\\hline\n"
+	} else {
+		out += "\\begin{tabular}{rll}\n\\hline\n"
+		out += "Rank & Proposal & Majority Grade \\\\\n\\hline\n"
+	}
+	for _, proposalResult := range proposalsResults {
+		out += fmt.Sprintf(
+			"%d & %s & %s",
+			proposalResult.Rank,
+			escapeLatex(proposals[proposalResult.Index])+makeLatexElectedMark(options, proposalResult.Index),
+			escapeLatex(grades[proposalResult.Analysis.MedianGrade]),
+		)
+		if showsScores(options) {
+			out += " & " + formatScore(proposalResult.Score)
+		}
+		out += " \\\\\n"
+	}
+	out += "\\hline\n\\end{tabular}\n\n"
+	if nil != getDeliberation(options).Winners {
+		out += "$\\star$ " + escapeLatex(strings.TrimSpace(makeWinnersNote(options, proposals, ""))) + "\n\n"
+	}
+
+	// II. Merit profiles
+	proposalsLabels := make([]string, 0, len(proposalsResults))
+	for _, proposalResult := range proposalsResults {
+		proposalsLabels = append(proposalsLabels, "{"+escapeLatex(
+			truncateString(proposals[proposalResult.Index], 23, '…'),
+		)+makeLatexElectedMark(options, proposalResult.Index)+"}")
+	}
+	legendEntries := make([]string, 0, len(grades))
+	for _, gradeIndex := range gradesIndices {
+		legendEntries = append(legendEntries, "{"+escapeLatex(grades[gradeIndex])+"}")
+	}
+
+	chartBottom := strconv.FormatFloat(float64(len(proposalsResults))-0.5, 'f', -1, 64)
+
+	out += "\\begin{tikzpicture}\n"
+	out += "\\begin{axis}[\n"
+	out += "    title={" + makeTitle("Merit Profiles", options) + "},\n"
+	out += "    xbar stacked,\n"
+	out += "    bar width=0.6cm,\n"
+	out += "    width=\\linewidth,\n"
+	out += "    height=" + strconv.Itoa(3+len(proposalsResults)) + "cm,\n"
+	out += "    xmin=0, xmax=100,\n"
+	out += "    xticklabel={$\\pgfmathprintnumber{\\tick}\\%$},\n"
+	out += "    ymin=-0.5, ymax=" + chartBottom + ",\n"
+	out += "    y dir=reverse,\n"
+	out += "    ytick={0,...," + strconv.Itoa(len(proposalsResults)-1) + "},\n"
+	out += "    yticklabels={" + strings.Join(proposalsLabels, ",") + "},\n"
+	out += "    legend style={at={(0.5,-0.15)}, anchor=north, legend columns=-1},\n"
+	out += "    legend entries={" + strings.Join(legendEntries, ",") + "},\n"
+	out += "]\n"
+	for _, gradeIndex := range gradesIndices {
+		coordinates := make([]string, 0, len(proposalsResults))
+		for i, proposalResult := range proposalsResults {
+			proposalTally := pollTally.Proposals[proposalResult.Index]
+			percentage := 0.0
+			amountOfJudgments := proposalTally.CountJudgments()
+			if amountOfJudgments > 0 {
+				percentage = 100.0 * float64(proposalTally.Tally[gradeIndex]) / float64(amountOfJudgments)
+			}
+			coordinates = append(coordinates, fmt.Sprintf(
+				"(%s,%d)",
+				strconv.FormatFloat(percentage, 'f', -1, 64),
+				i,
+			))
+		}
+		out += fmt.Sprintf(
+			"\\addplot[fill=mjGrade%d, draw=white] coordinates {%s};\n",
+			gradeIndex,
+			strings.Join(coordinates, " "),
+		)
+	}
+	out += "% Median vertical dotted bar\n"
+	out += "\\draw[dashed, thick] (axis cs:50,-0.5) -- (axis cs:50," + chartBottom + ");\n"
+	out += "\\end{axis}\n"
+	out += "\\end{tikzpicture}"
+
+	return out, nil
+}
+
+// escapeLatex escapes the characters that LaTeX would otherwise interpret
+func escapeLatex(text string) string {
+	replacer := strings.NewReplacer(
+		"\\", "\\textbackslash{}",
+		"&", "\\&",
+		"%", "\\%",
+		"$", "\\$",
+		"#", "\\#",
+		"_", "\\_",
+		"{", "\\{",
+		"}", "\\}",
+		"~", "\\textasciitilde{}",
+		"^", "\\textasciicircum{}",
+		"\n", " ",
+	)
+	return replacer.Replace(strings.TrimSpace(text))
+}
+
+// makeLatexElectedMark marks the elected proposals with a star, since the check mark is not in the LaTeX core
+func makeLatexElectedMark(options *Options, proposalIndex int) string {
+	if isElected(options, proposalIndex) {
+		return " $\\star$"
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+package formatter
+
+import (
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+	"strconv"
+)
+
+// marginsNote explains the margins shown by the text formatter
+const marginsNote = "Margins: judgments changed by one grade / to any grade, " +
+	"to swap with the next proposal (Lead) or with the winner (To win)."
+
+// proposalMargins are the margins of a proposal: over the next one in the ranking, and behind the winner
+type proposalMargins struct {
+	Lead  *analysis.Margin // nil for the last proposal of the ranking
+	ToWin *analysis.Margin // nil for the winner
+}
+
+// makeProposalsMargins gathers the margins of each proposal, in the order they were submitted
+func makeProposalsMargins(margins *analysis.Margins, amountOfProposals int) []proposalMargins {
+	proposalsMargins := make([]proposalMargins, amountOfProposals)
+	for i := range margins.Adjacent {
+		proposalsMargins[margins.Adjacent[i].Above].Lead = &margins.Adjacent[i]
+	}
+	for i := range margins.Winner {
+		proposalsMargins[margins.Winner[i].Below].ToWin = &margins.Winner[i]
+	}
+	return proposalsMargins
+}
+
+// formatMargin formats a margin like "5/3", for 5 changes of a judgment by one grade or 3 changes to any grade,
+// or "–" when there is no margin, or when no change could swap the proposals
+func formatMargin(margin *analysis.Margin) string {
+	if nil == margin || margin.AnyGradeMoves < 0 {
+		return "–"
+	}
+	return formatMarginAmount(margin.OneGradeMoves) + "/" + formatMarginAmount(margin.AnyGradeMoves)
+}
+
+// formatMarginAmount formats an amount of judgments of a margin, empty when there is none
+func formatMarginAmount(amount float64) string {
+	if amount < 0 {
+		return ""
+	}
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
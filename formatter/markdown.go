@@ -0,0 +1,188 @@
+package formatter
+
+import (
+	"fmt"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"strings"
+)
+
+// Unicode blocks used to draw the merit bars, from the "worst" grade (lowest) to the "best" grade (highest)
+const markdownBarShades = "▁▂▃▄▅▆▇█"
+
+// Amount of characters of the merit bars
+const markdownBarWidth = 24
+
+// MarkdownFormatter formats the results as a GitHub-flavored Markdown table,
+// to paste in issues, pull requests, wikis and READMEs.
+type MarkdownFormatter struct{}
+
+func init() {
+	Register(Registration{
+		Name:        "markdown",
+		Aliases:     []string{"md"},
+		Description: "ranking table, to paste in issues and wikis",
+		MediaType:   "text/markdown; charset=utf-8",
+		Create:      func() Formatter { return &MarkdownFormatter{} },
+	})
+}
+
+// Format the provided results
+func (t *MarkdownFormatter) Format(
+	pollTally *judgment.PollTally,
+	result *judgment.PollResult,
+	proposals []string,
+	grades []string,
+	options *Options,
+) (string, error) {
+	winnersNote := makeWinnersNote(options, proposals, electedMark)
+	proposals = markElectedProposals(proposals, options, electedMark)
+	proposalsResults := result.Proposals
+	if options.Sorted {
+		proposalsResults = result.ProposalsSorted
+	}
+
+	gradesIndices := make([]int, 0, len(grades))
+	for i := range grades {
+		gradeIndex := i
+		if options.GreenToRed {
+			gradeIndex = len(grades) - 1 - i
+		}
+		gradesIndices = append(gradesIndices, gradeIndex)
+	}
+
+	amountOfProposalsPerRank := make(map[int]int)
+	for _, proposalResult := range proposalsResults {
+		amountOfProposalsPerRank[proposalResult.Rank]++
+	}
+	hasTies := false
+
+	out := "| Rank | Proposal | Majority Grade |"
+	separator := "|---:|:---|:---|"
+	if showsScores(options) {
+		out += " Score |"
+		separator += "---:|"
+	}
+	for _, gradeIndex := range gradesIndices {
+		out += " " + escapeMarkdown(grades[gradeIndex]) + " |"
+		separator += "---:|"
+	}
+	if options.MeritBars {
+		out += " Merit Profile |"
+		separator += ":---|"
+	}
+	out += "\n" + separator + "\n"
+
+	for _, proposalResult := range proposalsResults {
+		proposalTally := pollTally.Proposals[proposalResult.Index]
+		amountOfJudgments := proposalTally.CountJudgments()
+
+		rank := fmt.Sprintf("%d", proposalResult.Rank)
+		if amountOfProposalsPerRank[proposalResult.Rank] > 1 {
+			rank += "[^tie]"
+			hasTies = true
+		}
+		out += fmt.Sprintf(
+			"| %s | %s | %s |",
+			rank,
+			escapeMarkdown(proposals[proposalResult.Index]),
+			escapeMarkdown(grades[proposalResult.Analysis.MedianGrade]),
+		)
+		if showsScores(options) {
+			out += " " + formatScore(proposalResult.Score) + " |"
+		}
+		for _, gradeIndex := range gradesIndices {
+			percentage := 0.0
+			if amountOfJudgments > 0 {
+				percentage = 100.0 * float64(proposalTally.Tally[gradeIndex]) / float64(amountOfJudgments)
+			}
+			out += fmt.Sprintf(
+				" %s (%.1f%%) |",
+				formatAmount(proposalTally.Tally[gradeIndex], options.Scale),
+				percentage,
+			)
+		}
+		if options.MeritBars {
+			out += " `" + makeMarkdownMeritBar(proposalTally, markdownBarWidth, options.GreenToRed) + "` |"
+		}
+		out += "\n"
+	}
+
+	if options.MeritBars {
+		out += "\n"
+		legend := make([]string, 0, len(grades))
+		for _, gradeIndex := range gradesIndices {
+			legend = append(legend, fmt.Sprintf(
+				"`%s` %s",
+				getMarkdownShade(gradeIndex, len(grades)),
+				escapeMarkdown(grades[gradeIndex]),
+			))
+		}
+		out += "Merit profiles: " + strings.Join(legend, " · ") + " ; the median is marked with `│`.\n"
+	}
+
+	if hasTies && showsScores(options) {
+		out += "\n[^tie]: Proposals sharing a rank are perfectly tied: their scores are exactly equal.\n"
+	} else if hasTies {
+		out += "\n[^tie]: Proposals sharing a rank are perfectly tied: " +
+			"Majority Judgment cannot tell them apart, since their merit profiles are equivalent.\n"
+	}
+
+	if isWeighted(options) {
+		out += "\n" + weightedNote + "\n"
+	}
+
+	if "" != winnersNote {
+		out += "\n" + escapeMarkdown(winnersNote) + "\n"
+	}
+
+	return out, nil
+}
+
+// makeMarkdownMeritBar draws the merit profile with unicode blocks of different heights
+func makeMarkdownMeritBar(tally *judgment.ProposalTally, width int, greenToRed bool) (bar string) {
+	if width%2 == 0 {
+		width++ // so that there is a middle character for the median
+	}
+	for cursor := 0; cursor < width; cursor++ {
+		if cursor == width/2 {
+			bar += "│"
+			continue
+		}
+		ratio := float64(cursor) / float64(width)
+		if greenToRed {
+			ratio = float64(width-cursor-1) / float64(width)
+		}
+		gradeIndex, _ := getGradeAtRatio(tally, ratio)
+		bar += getMarkdownShade(gradeIndex, len(tally.Tally))
+	}
+
+	return
+}
+
+// getMarkdownShade returns the unicode block of the grade, higher for better grades
+func getMarkdownShade(gradeIndex int, amountOfGrades int) string {
+	shades := []rune(markdownBarShades)
+	if amountOfGrades < 2 {
+		return string(shades[len(shades)-1])
+	}
+	shadeIndex := gradeIndex * (len(shades) - 1) / (amountOfGrades - 1)
+	if shadeIndex >= len(shades) {
+		shadeIndex = len(shades) - 1
+	}
+	return string(shades[shadeIndex])
+}
+
+// escapeMarkdown escapes the characters that would break the table
+func escapeMarkdown(text string) string {
+	replacer := strings.NewReplacer(
+		"|", "\\|",
+		"*", "\\*",
+		"_", "\\_",
+		"`", "\\`",
+		"[", "\\[",
+		"]", "\\]",
+		"<", "&lt;",
+		"\n", " ",
+	)
+	return replacer.Replace(strings.TrimSpace(text))
+}
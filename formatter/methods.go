@@ -0,0 +1,76 @@
+package formatter
+
+import (
+	"fmt"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+	"strconv"
+)
+
+// methodColumn is a column of results of another voting method, shown next to the ranks by --compare-methods
+type methodColumn struct {
+	Title    string // for humans, like "Approval ≥ good"
+	Name     string // for machines, like "Approval"
+	Value    func(result *analysis.MethodsResult) float64
+	Format   func(value float64) string // for humans
+	Rank     func(result *analysis.MethodsResult) int
+	IsMarked func(result *analysis.MethodsResult) bool // whether to mark the value, like the Condorcet winner
+}
+
+// makeMethodColumns lists the columns of the other voting methods, Copeland only being available with ballots
+func makeMethodColumns(methods *analysis.MethodsComparison, grades []string) []methodColumn {
+	formatFloat := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', 2, 64)
+	}
+	isNeverMarked := func(result *analysis.MethodsResult) bool {
+		return false
+	}
+
+	columns := []methodColumn{
+		{
+			Title:    "Mean",
+			Name:     "MeanGrade",
+			Value:    func(result *analysis.MethodsResult) float64 { return result.MeanGrade },
+			Format:   formatFloat,
+			Rank:     func(result *analysis.MethodsResult) int { return result.MeanRank },
+			IsMarked: isNeverMarked,
+		},
+		{
+			Title:    "Approval ≥ " + getGradeName(grades, methods.ApprovalThreshold),
+			Name:     "Approval",
+			Value:    func(result *analysis.MethodsResult) float64 { return result.Approval },
+			Format:   func(value float64) string { return fmt.Sprintf("%.1f%%", value) },
+			Rank:     func(result *analysis.MethodsResult) int { return result.ApprovalRank },
+			IsMarked: isNeverMarked,
+		},
+		{
+			Title:    "Borda",
+			Name:     "Borda",
+			Value:    func(result *analysis.MethodsResult) float64 { return result.Borda },
+			Format:   formatFloat,
+			Rank:     func(result *analysis.MethodsResult) int { return result.BordaRank },
+			IsMarked: isNeverMarked,
+		},
+	}
+	if methods.HasBallots {
+		columns = append(columns, methodColumn{
+			Title:    "Copeland",
+			Name:     "Copeland",
+			Value:    func(result *analysis.MethodsResult) float64 { return result.Copeland },
+			Format:   func(value float64) string { return strconv.FormatFloat(value, 'f', -1, 64) },
+			Rank:     func(result *analysis.MethodsResult) int { return result.CopelandRank },
+			IsMarked: func(result *analysis.MethodsResult) bool { return result.CondorcetWinner },
+		})
+	}
+
+	return columns
+}
+
+// formatMethodCell formats the value and the rank of a proposal in a column of another voting method,
+// like "3.06 #2", with a * for the Condorcet winner
+func formatMethodCell(column *methodColumn, result *analysis.MethodsResult) string {
+	cell := column.Format(column.Value(result)) + " #" + strconv.Itoa(column.Rank(result))
+	if column.IsMarked(result) {
+		cell += "*"
+	}
+	return cell
+}
@@ -0,0 +1,146 @@
+package formatter
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PluginProtocolVersion is the version of the protocol between mj and its plugins.
+// Plugins receive it in the MJ_PLUGIN_PROTOCOL environment variable, and should refuse versions they do not know.
+//
+// The JSON document of the json format is written to the plugin's standard input,
+// the standard output of the plugin is relayed as is,
+// and a non-zero exit code means failure, explained on the standard error.
+// The options are given in these environment variables:
+//   - MJ_PLUGIN_PROTOCOL, this version
+//   - MJ_FORMAT, the name of the format, as in --format <name>
+//   - MJ_SCALE, the tallies of the document were multiplied by it so that they are integers, like 1 or 3
+//   - MJ_SORTED, whether the proposals should be sorted by rank, true or false
+//   - MJ_WIDTH, the desired width, in characters
+//   - MJ_COLORIZED, whether colors are welcome, true or false
+//   - MJ_GREEN_TO_RED, whether the grades should be displayed from best to worst, true or false
+//   - MJ_METHOD, the deliberation method of the results, like majority or usual
+//   - MJ_WEIGHTED, whether the judges were weighted, true or false
+//   - MJ_WINNERS, the amount of proposals elected from the top of the ranking, or 0 when none were
+const PluginProtocolVersion = 1
+
+// Executables named like mj-format-<name> are plugins providing the format <name>
+const pluginPrefix = "mj-format-"
+
+// Plugin names are restricted, so that --format may not be used to run arbitrary paths
+var pluginNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// PluginFormatter runs an external executable, a plugin, that does the formatting.
+// See PluginProtocolVersion for how they communicate.
+type PluginFormatter struct {
+	Name string // the format provided by the plugin
+	Path string // the executable of the plugin
+}
+
+// IsBinary is part of the BinaryFormatter interface.
+// The output of the plugin is relayed as is, since it may be anything.
+func (t *PluginFormatter) IsBinary() bool {
+	return true
+}
+
+// Format the provided results
+func (t *PluginFormatter) Format(
+	pollTally *judgment.PollTally,
+	result *judgment.PollResult,
+	proposals []string,
+	grades []string,
+	options *Options,
+) (string, error) {
+	document, documentErr := (&JsonFormatter{}).Format(pollTally, result, proposals, grades, options)
+	if nil != documentErr {
+		return "", documentErr
+	}
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	command := exec.Command(t.Path)
+	command.Stdin = strings.NewReader(document)
+	command.Stdout = stdout
+	command.Stderr = stderr
+	command.Env = append(
+		os.Environ(),
+		"MJ_PLUGIN_PROTOCOL="+strconv.Itoa(PluginProtocolVersion),
+		"MJ_FORMAT="+t.Name,
+		"MJ_SCALE="+strconv.FormatFloat(options.Scale, 'f', -1, 64),
+		"MJ_SORTED="+strconv.FormatBool(options.Sorted),
+		"MJ_WIDTH="+strconv.Itoa(options.Width),
+		"MJ_COLORIZED="+strconv.FormatBool(options.Colorized),
+		"MJ_GREEN_TO_RED="+strconv.FormatBool(options.GreenToRed),
+		"MJ_METHOD="+options.Method,
+		"MJ_WEIGHTED="+strconv.FormatBool(isWeighted(options)),
+		"MJ_WINNERS="+strconv.Itoa(countSeats(options)),
+	)
+
+	runErr := command.Run()
+	if nil != runErr {
+		explanation := strings.TrimSpace(stderr.String())
+		if "" == explanation {
+			explanation = "(no explanation on its standard error)"
+		}
+		return "", fmt.Errorf("plugin `%s` failed: %s: %s", t.Path, runErr, explanation)
+	}
+	// Warnings of a successful plugin are still worth reading
+	_, _ = os.Stderr.Write(stderr.Bytes())
+
+	return stdout.String(), nil
+}
+
+// FindPlugin looks for the executable mj-format-<name>, in the directory first (if any), and then in the PATH
+func FindPlugin(name string, directory string) (string, bool) {
+	if !pluginNameRegexp.MatchString(name) {
+		return "", false
+	}
+	if "" != directory {
+		path, lookErr := exec.LookPath(filepath.Join(directory, pluginPrefix+name))
+		if nil == lookErr {
+			return path, true
+		}
+	}
+	path, lookErr := exec.LookPath(pluginPrefix + name)
+	if nil == lookErr {
+		return path, true
+	}
+
+	return "", false
+}
+
+// ListPlugins finds the names of the formats provided by plugins, in the directory (if any) and in the PATH
+func ListPlugins(directory string) []string {
+	directories := filepath.SplitList(os.Getenv("PATH"))
+	if "" != directory {
+		directories = append([]string{directory}, directories...)
+	}
+
+	found := make(map[string]bool)
+	for _, dir := range directories {
+		matches, _ := filepath.Glob(filepath.Join(dir, pluginPrefix+"*"))
+		for _, match := range matches {
+			name := strings.TrimPrefix(filepath.Base(match), pluginPrefix)
+			name = strings.TrimSuffix(name, filepath.Ext(name)) // .exe and such
+			if _, isPlugin := FindPlugin(name, directory); isPlugin {
+				found[name] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,208 @@
+package formatter
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+)
+
+// Size of the pixels of the font, in pixels of the image
+const pngFontScale = 2
+
+var pngBackgroundColor = color.RGBA{R: 0xf0, G: 0xf0, B: 0xf0, A: 0xff}
+var pngTextColor = color.RGBA{R: 0x33, G: 0x33, B: 0x33, A: 0xff}
+
+// PngMeritFormatter draws the merit profiles of the proposals in a PNG image.
+// It only uses the standard library, and therefore embeds its own tiny bitmap font.
+type PngMeritFormatter struct{}
+
+func init() {
+	Register(Registration{
+		Name:        "png",
+		Chart:       "merit",
+		Description: "PNG image of the merit profiles",
+		MediaType:   "image/png",
+		Create:      func() Formatter { return &PngMeritFormatter{} },
+	})
+}
+
+// IsBinary is part of the BinaryFormatter interface
+func (t *PngMeritFormatter) IsBinary() bool {
+	return true
+}
+
+// Format the provided results
+func (t *PngMeritFormatter) Format(
+	pollTally *judgment.PollTally,
+	result *judgment.PollResult,
+	proposals []string,
+	grades []string,
+	options *Options,
+) (string, error) {
+	proposals = markElectedProposals(proposals, options, electedMarkAscii)
+	proposalsResults := result.Proposals
+	if options.Sorted {
+		proposalsResults = result.ProposalsSorted
+	}
+
+	palette := judgment.CreateDefaultPalette(len(grades))
+
+	biggestRank := 1
+	for _, proposalResult := range proposalsResults {
+		if biggestRank < proposalResult.Rank {
+			biggestRank = proposalResult.Rank
+		}
+	}
+	amountOfDigitsForRank := countDigits(biggestRank)
+
+	amountOfCharactersForProposal := 1
+	maximumAmountOfCharactersForProposal := 30
+	for _, proposal := range proposals {
+		thatProposalLength := measureStringLength(proposal)
+		if thatProposalLength > amountOfCharactersForProposal {
+			amountOfCharactersForProposal = thatProposalLength
+		}
+	}
+	if amountOfCharactersForProposal > maximumAmountOfCharactersForProposal {
+		amountOfCharactersForProposal = maximumAmountOfCharactersForProposal
+	}
+
+	const margin = 20
+	const barHeight = 28
+	const barSpacing = 8
+	const textHeight = pngGlyphHeight * pngFontScale
+	const titleHeight = textHeight * 4
+	const legendLineHeight = textHeight * 2
+
+	width := getPixelsWidth(options)
+	charWidth := measurePngText(" ", pngFontScale)
+	rankWidth := (amountOfDigitsForRank + 1) * charWidth
+	scoreWidth := 0
+	if showsScores(options) {
+		for _, proposalResult := range proposalsResults {
+			thatScoreWidth := charWidth + measurePngText(formatScore(proposalResult.Score), pngFontScale)
+			if thatScoreWidth > scoreWidth {
+				scoreWidth = thatScoreWidth
+			}
+		}
+	}
+	rankWidth += scoreWidth
+	labelsWidth := rankWidth + charWidth + amountOfCharactersForProposal*charWidth + charWidth
+	chartX := margin + labelsWidth
+	chartWidth := width - chartX - margin
+	if chartWidth < 100 {
+		chartWidth = 100
+		width = chartX + chartWidth + margin
+	}
+	chartHeight := len(proposalsResults) * (barHeight + barSpacing)
+
+	// Lay out the legend first, in order to know the height of the image
+	type legendItem struct {
+		x, y       int
+		name       string
+		gradeIndex int
+	}
+	legendItems := make([]legendItem, 0, len(grades))
+	legendY := titleHeight + chartHeight + margin
+	cursorX := chartX
+	cursorY := legendY
+	for i := range grades {
+		gradeIndex := i
+		if options.GreenToRed {
+			gradeIndex = len(grades) - 1 - i
+		}
+		name := truncateString(grades[gradeIndex], 30, '…')
+		itemWidth := textHeight + charWidth + measurePngText(name, pngFontScale) + charWidth*2
+		if cursorX+itemWidth > chartX+chartWidth && cursorX > chartX {
+			cursorX = chartX
+			cursorY += legendLineHeight
+		}
+		legendItems = append(legendItems, legendItem{x: cursorX, y: cursorY, name: name, gradeIndex: gradeIndex})
+		cursorX += itemWidth
+	}
+	height := cursorY + legendLineHeight + margin
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(pngBackgroundColor), image.Point{}, draw.Src)
+
+	title := makeTitle("Merit Profiles", options)
+	drawPngText(img, (width-measurePngText(title, pngFontScale))/2, textHeight, title, pngFontScale, pngTextColor)
+
+	for i, proposalResult := range proposalsResults {
+		y := titleHeight + i*(barHeight+barSpacing)
+		textY := y + (barHeight-textHeight)/2
+		rank := fmt.Sprintf("#%0"+strconv.Itoa(amountOfDigitsForRank)+"d", proposalResult.Rank)
+		drawPngText(img, margin, textY, rank, pngFontScale, pngTextColor)
+		if showsScores(options) {
+			scoreX := margin + (amountOfDigitsForRank+2)*charWidth
+			drawPngText(img, scoreX, textY, formatScore(proposalResult.Score), pngFontScale, pngTextColor)
+		}
+		name := truncateString(proposals[proposalResult.Index], amountOfCharactersForProposal, '…')
+		nameX := chartX - charWidth - measurePngText(name, pngFontScale)
+		drawPngText(img, nameX, textY, name, pngFontScale, pngTextColor)
+
+		drawPngMeritProfile(
+			img,
+			pollTally.Proposals[proposalResult.Index],
+			image.Rect(chartX, y, chartX+chartWidth, y+barHeight),
+			palette,
+			options.GreenToRed,
+		)
+	}
+
+	// Median vertical dashed bar
+	medianX := chartX + chartWidth/2
+	for y := titleHeight - barSpacing; y < titleHeight+chartHeight; y += 10 {
+		dash := image.Rect(medianX-1, y, medianX+1, y+6)
+		draw.Draw(img, dash, image.NewUniform(pngTextColor), image.Point{}, draw.Src)
+	}
+
+	for _, item := range legendItems {
+		square := image.Rect(item.x, item.y, item.x+textHeight, item.y+textHeight)
+		draw.Draw(img, square, image.NewUniform(palette[item.gradeIndex]), image.Point{}, draw.Src)
+		drawPngText(img, item.x+textHeight+charWidth, item.y, item.name, pngFontScale, pngTextColor)
+	}
+
+	buffer := new(bytes.Buffer)
+	pngErr := png.Encode(buffer, img)
+	if pngErr != nil {
+		return "", pngErr
+	}
+
+	return buffer.String(), nil
+}
+
+// drawPngMeritProfile draws the merit profile of a proposal as a horizontal bar of stacked grades
+func drawPngMeritProfile(
+	img draw.Image,
+	tally *judgment.ProposalTally,
+	bounds image.Rectangle,
+	palette color.Palette,
+	greenToRed bool,
+) {
+	amountOfJudgments := tally.CountJudgments()
+	if 0 == amountOfJudgments {
+		return
+	}
+
+	width := float64(bounds.Dx())
+	total := float64(amountOfJudgments)
+	cumulated := 0.0
+	for i := range tally.Tally {
+		gradeIndex := i
+		if greenToRed {
+			gradeIndex = len(tally.Tally) - 1 - i
+		}
+		// Compute both ends from the cumulated tally, so that rounding errors do not pile up
+		startX := bounds.Min.X + int(width*cumulated/total)
+		cumulated += float64(tally.Tally[gradeIndex])
+		endX := bounds.Min.X + int(width*cumulated/total)
+		gradeBar := image.Rect(startX, bounds.Min.Y, endX, bounds.Max.Y)
+		draw.Draw(img, gradeBar, image.NewUniform(palette[gradeIndex]), image.Point{}, draw.Src)
+	}
+}
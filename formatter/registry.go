@@ -0,0 +1,142 @@
+package formatter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Registration describes a formatter to the registry.
+// Formatters register themselves in the init() of their own file.
+type Registration struct {
+	Name        string           // as in --format <name>
+	Aliases     []string         // other names accepted by --format
+	Chart       string           // chart drawn, as in --chart <chart>, or empty when the formatter draws no chart
+	Description string           // one line, for --format help
+	MediaType   string           // of the output, as in the Content-Type of HTTP responses
+	Create      func() Formatter // makes a new formatter, ready to Format
+	// Fallback is picked when the chart is not supported by the format, instead of failing.
+	// Only the text merit profiles are, since the text format always drew them for unknown charts.
+	Fallback bool
+}
+
+// Formatters drawing charts may also be asked for as <name>-<chart> or <name>_<chart>, like gnuplot-opinion.
+const chartSeparators = "-_"
+
+var registrations []Registration
+
+// Register adds a formatter to the registry.
+// Formatters drawing different charts may share the same name, and are then picked by --chart.
+func Register(registration Registration) {
+	registrations = append(registrations, registration)
+	sort.SliceStable(registrations, func(i, j int) bool {
+		if registrations[i].Name == registrations[j].Name {
+			return registrations[i].Chart < registrations[j].Chart
+		}
+		return registrations[i].Name < registrations[j].Name
+	})
+}
+
+// Registrations lists the registered formatters, sorted by name and chart
+func Registrations() []Registration {
+	return append([]Registration{}, registrations...)
+}
+
+// Names lists the distinct names of the registered formatters, sorted
+func Names() []string {
+	names := make([]string, 0, len(registrations))
+	for _, registration := range registrations {
+		if 0 == len(names) || names[len(names)-1] != registration.Name {
+			names = append(names, registration.Name)
+		}
+	}
+	return names
+}
+
+// IsRegistered tells whether a formatter is registered under that name or alias
+func IsRegistered(format string) bool {
+	return 0 < len(findRegistrations(strings.ToLower(strings.TrimSpace(format))))
+}
+
+// Lookup creates the formatter registered for the format, picking the variant drawing the chart if needed
+func Lookup(format string, chart string) (Formatter, error) {
+	registration, findErr := Find(format, chart)
+	if nil != findErr {
+		return nil, findErr
+	}
+
+	return registration.Create(), nil
+}
+
+// Find the registration of the format, picking the variant drawing the chart if needed
+func Find(format string, chart string) (*Registration, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	candidates := findRegistrations(format)
+	if 0 == len(candidates) {
+		// Perhaps something like gnuplot-opinion, which overrides the chart
+		for _, registration := range registrations {
+			if "" == registration.Chart {
+				continue
+			}
+			for _, separator := range chartSeparators {
+				suffix := string(separator) + registration.Chart
+				if !strings.HasSuffix(format, suffix) {
+					continue
+				}
+				if registration.matches(strings.TrimSuffix(format, suffix)) {
+					found := registration
+					return &found, nil
+				}
+			}
+		}
+
+		return nil, fmt.Errorf(
+			"format `%s` is not supported.  Supported formats: %s",
+			format, strings.Join(Names(), ", "),
+		)
+	}
+
+	charts := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if "" == candidate.Chart || chart == candidate.Chart {
+			found := candidate
+			return &found, nil
+		}
+		charts = append(charts, candidate.Chart)
+	}
+	for _, candidate := range candidates {
+		if candidate.Fallback {
+			found := candidate
+			return &found, nil
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"chart `%s` is not supported by the %s format.  Supported charts: %s",
+		chart, candidates[0].Name, strings.Join(charts, ", "),
+	)
+}
+
+// findRegistrations returns the registrations known under that name or alias
+func findRegistrations(format string) []Registration {
+	found := make([]Registration, 0, 2)
+	for _, registration := range registrations {
+		if registration.matches(format) {
+			found = append(found, registration)
+		}
+	}
+	return found
+}
+
+// matches tells whether the format is the name or one of the aliases of the registration
+func (r Registration) matches(format string) bool {
+	if format == r.Name {
+		return true
+	}
+	for _, alias := range r.Aliases {
+		if format == alias {
+			return true
+		}
+	}
+	return false
+}
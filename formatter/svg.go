@@ -0,0 +1,142 @@
+package formatter
+
+import (
+	"fmt"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"html"
+	"image/color"
+	"strings"
+)
+
+// Pixels per character of the desired width, since Options.Width is in characters
+const pixelsPerCharacter = 10
+
+// Font size of the texts of the charts, in pixels
+const svgFontSize = 14
+
+const svgBackgroundColor = "#f0f0f0"
+const svgTextColor = "#333333"
+
+// getPixelsWidth converts the desired width in characters into pixels, for images and drawings
+func getPixelsWidth(options *Options) int {
+	width := options.Width
+	if width <= 0 {
+		width = defaultWidth
+	}
+	return width * pixelsPerCharacter
+}
+
+// startSvg opens a standalone SVG document with a background and a title
+func startSvg(width int, height int, title string) string {
+	return fmt.Sprintf(
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" `+
+			`font-family="sans-serif" font-size="%d">`+"\n",
+		width, height, width, height, svgFontSize,
+	) +
+		fmt.Sprintf(`<title>%s</title>`+"\n", escapeSvg(title)) +
+		fmt.Sprintf(`<rect x="0" y="0" width="%d" height="%d" fill="%s"/>`+"\n", width, height, svgBackgroundColor) +
+		makeSvgText(width/2, svgFontSize*2, "middle", title, `font-weight="bold"`)
+}
+
+// endSvg closes the SVG document opened by startSvg
+func endSvg() string {
+	return "</svg>\n"
+}
+
+// makeSvgText writes some text, anchored at start, middle or end
+func makeSvgText(x int, y int, anchor string, text string, attributes string) string {
+	if "" != attributes {
+		attributes = " " + attributes
+	}
+	return fmt.Sprintf(
+		`<text x="%d" y="%d" text-anchor="%s" fill="%s"%s>%s</text>`+"\n",
+		x, y, anchor, svgTextColor, attributes, escapeSvg(text),
+	)
+}
+
+// makeSvgRect writes a filled rectangle, with a tooltip if provided
+func makeSvgRect(x float64, y float64, width float64, height float64, fill color.Color, tooltip string) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	rect := fmt.Sprintf(
+		`<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"`,
+		x, y, width, height, judgment.DumpColorHexString(fill, "#", false),
+	)
+	if "" == tooltip {
+		return rect + "/>\n"
+	}
+	return rect + fmt.Sprintf("><title>%s</title></rect>\n", escapeSvg(tooltip))
+}
+
+// makeSvgMeritProfile draws the merit profile of a proposal as a horizontal bar of stacked grades.
+// The median is drawn separately, since it is shared by all the profiles.
+func makeSvgMeritProfile(
+	tally *judgment.ProposalTally,
+	grades []string,
+	x float64,
+	y float64,
+	width float64,
+	height float64,
+	palette color.Palette,
+	greenToRed bool,
+) (svg string) {
+	amountOfJudgments := float64(tally.CountJudgments())
+	if 0 == amountOfJudgments {
+		return
+	}
+
+	cursor := x
+	for i := range tally.Tally {
+		gradeIndex := i
+		if greenToRed {
+			gradeIndex = len(tally.Tally) - 1 - i
+		}
+		gradeTally := float64(tally.Tally[gradeIndex])
+		gradeWidth := width * gradeTally / amountOfJudgments
+		tooltip := ""
+		if gradeIndex < len(grades) {
+			tooltip = fmt.Sprintf("%s: %.1f%%", grades[gradeIndex], 100.0*gradeTally/amountOfJudgments)
+		}
+		svg += makeSvgRect(cursor, y, gradeWidth, height, palette[gradeIndex], tooltip)
+		cursor += gradeWidth
+	}
+
+	return
+}
+
+// makeSvgLegend draws a legend of colored squares and their names, wrapped on as many lines as needed.
+// Returns the SVG and the height it takes.
+func makeSvgLegend(
+	names []string,
+	colors color.Palette,
+	x int,
+	y int,
+	width int,
+) (svg string, height int) {
+	const squareSize = svgFontSize
+	const spacing = svgFontSize
+	lineHeight := svgFontSize * 2
+
+	cursorX := x
+	cursorY := y
+	for i, name := range names {
+		name = truncateString(name, 30, '…')
+		itemWidth := squareSize + svgFontSize/2 + measureStringLength(name)*svgFontSize*6/10 + spacing
+		if cursorX+itemWidth > x+width && cursorX > x {
+			cursorX = x
+			cursorY += lineHeight
+		}
+		svg += makeSvgRect(float64(cursorX), float64(cursorY), squareSize, squareSize, colors[i], "")
+		svg += makeSvgText(cursorX+squareSize+svgFontSize/2, cursorY+squareSize-2, "start", name, "")
+		cursorX += itemWidth
+	}
+	height = cursorY - y + lineHeight
+
+	return
+}
+
+// escapeSvg escapes the texts that go into the SVG
+func escapeSvg(text string) string {
+	return html.EscapeString(strings.TrimSpace(text))
+}
@@ -0,0 +1,153 @@
+package formatter
+
+import (
+	"fmt"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"image/color"
+	"strconv"
+)
+
+// SvgMeritFormatter draws the merit profiles of the proposals as a standalone SVG
+type SvgMeritFormatter struct{}
+
+func init() {
+	Register(Registration{
+		Name:        "svg",
+		Chart:       "merit",
+		Description: "standalone SVG image of the merit profiles",
+		MediaType:   "image/svg+xml",
+		Create:      func() Formatter { return &SvgMeritFormatter{} },
+	})
+}
+
+// Format the provided results
+func (t *SvgMeritFormatter) Format(
+	pollTally *judgment.PollTally,
+	result *judgment.PollResult,
+	proposals []string,
+	grades []string,
+	options *Options,
+) (string, error) {
+	proposalsResults := result.Proposals
+	if options.Sorted {
+		proposalsResults = result.ProposalsSorted
+	}
+
+	palette := judgment.CreateDefaultPalette(len(grades))
+
+	biggestRank := 1
+	for _, proposalResult := range proposalsResults {
+		if biggestRank < proposalResult.Rank {
+			biggestRank = proposalResult.Rank
+		}
+	}
+	amountOfDigitsForRank := countDigits(biggestRank)
+
+	amountOfCharactersForProposal := 1
+	maximumAmountOfCharactersForProposal := 30
+	for _, proposal := range proposals {
+		thatProposalLength := measureStringLength(proposal)
+		if thatProposalLength > amountOfCharactersForProposal {
+			amountOfCharactersForProposal = thatProposalLength
+		}
+	}
+	if amountOfCharactersForProposal > maximumAmountOfCharactersForProposal {
+		amountOfCharactersForProposal = maximumAmountOfCharactersForProposal
+	}
+	amountOfCharactersForMark := 0
+	if countSeats(options) > 0 {
+		amountOfCharactersForMark = measureStringLength(" " + electedMark)
+	}
+
+	const margin = 20
+	const barHeight = 28
+	const barSpacing = 8
+	const titleHeight = svgFontSize * 4
+
+	amountOfCharactersForScore := 0
+	if showsScores(options) {
+		for _, proposalResult := range proposalsResults {
+			if measureStringLength(formatScore(proposalResult.Score))+1 > amountOfCharactersForScore {
+				amountOfCharactersForScore = measureStringLength(formatScore(proposalResult.Score)) + 1
+			}
+		}
+	}
+
+	width := getPixelsWidth(options)
+	labelsWidth := (amountOfDigitsForRank + 3 + amountOfCharactersForScore + amountOfCharactersForProposal + amountOfCharactersForMark) *
+		svgFontSize * 6 / 10
+	chartX := margin + labelsWidth
+	chartWidth := width - chartX - margin
+	if chartWidth < 100 {
+		chartWidth = 100
+		width = chartX + chartWidth + margin
+	}
+	chartHeight := len(proposalsResults) * (barHeight + barSpacing)
+
+	legendNames := make([]string, 0, len(grades))
+	legendColors := make(color.Palette, 0, len(grades))
+	for i := range grades {
+		gradeIndex := i
+		if options.GreenToRed {
+			gradeIndex = len(grades) - 1 - i
+		}
+		legendNames = append(legendNames, grades[gradeIndex])
+		legendColors = append(legendColors, palette[gradeIndex])
+	}
+	legend, legendHeight := makeSvgLegend(legendNames, legendColors, chartX, titleHeight+chartHeight+margin, chartWidth)
+
+	height := titleHeight + chartHeight + margin + legendHeight + margin
+
+	svg := startSvg(width, height, makeTitle("Merit Profiles", options))
+
+	for i, proposalResult := range proposalsResults {
+		y := titleHeight + i*(barHeight+barSpacing)
+		textY := y + barHeight/2 + svgFontSize/3
+		svg += makeSvgText(
+			margin, textY, "start",
+			fmt.Sprintf("#%0"+strconv.Itoa(amountOfDigitsForRank)+"d", proposalResult.Rank),
+			`font-weight="bold"`,
+		)
+		if showsScores(options) {
+			svg += makeSvgText(
+				margin+(amountOfDigitsForRank+2)*svgFontSize*6/10, textY, "start",
+				formatScore(proposalResult.Score),
+				"",
+			)
+		}
+		nameAttributes := ""
+		if isElected(options, proposalResult.Index) {
+			nameAttributes = `font-weight="bold"`
+		}
+		svg += makeSvgText(
+			chartX-svgFontSize/2, textY, "end",
+			markElectedProposal(
+				truncateString(proposals[proposalResult.Index], amountOfCharactersForProposal, '…'),
+				proposalResult.Index, options, electedMark,
+			),
+			nameAttributes,
+		)
+		svg += makeSvgMeritProfile(
+			pollTally.Proposals[proposalResult.Index],
+			grades,
+			float64(chartX),
+			float64(y),
+			float64(chartWidth),
+			barHeight,
+			palette,
+			options.GreenToRed,
+		)
+	}
+
+	// Median vertical dashed bar
+	medianX := chartX + chartWidth/2
+	svg += fmt.Sprintf(
+		`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="2" stroke-dasharray="6,4"/>`+"\n",
+		medianX, titleHeight-barSpacing, medianX, titleHeight+chartHeight, svgTextColor,
+	)
+
+	svg += legend
+	svg += endSvg()
+
+	return svg, nil
+}
@@ -0,0 +1,127 @@
+package formatter
+
+import (
+	"fmt"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"image/color"
+	"strconv"
+)
+
+// SvgOpinionFormatter draws the opinion profile of the poll as a standalone SVG,
+// that is, for each grade, the stacked amounts of judgments received by each proposal.
+type SvgOpinionFormatter struct{}
+
+func init() {
+	Register(Registration{
+		Name:        "svg",
+		Chart:       "opinion",
+		Description: "standalone SVG image of the opinion profile",
+		MediaType:   "image/svg+xml",
+		Create:      func() Formatter { return &SvgOpinionFormatter{} },
+	})
+}
+
+// Format the provided results
+func (t *SvgOpinionFormatter) Format(
+	pollTally *judgment.PollTally,
+	result *judgment.PollResult,
+	proposals []string,
+	grades []string,
+	options *Options,
+) (string, error) {
+	proposalsResults := result.Proposals
+	if options.Sorted {
+		proposalsResults = result.ProposalsSorted
+	}
+
+	palette := judgment.CreateDefaultPalette(len(proposals))
+
+	maximumAmountOfJudgmentsForGrade := uint64(0)
+	for gradeIndex := range grades {
+		cumulatedAmountOfJudgmentsForGrade := uint64(0)
+		for _, proposalTally := range pollTally.Proposals {
+			cumulatedAmountOfJudgmentsForGrade += proposalTally.Tally[gradeIndex]
+		}
+		if cumulatedAmountOfJudgmentsForGrade > maximumAmountOfJudgmentsForGrade {
+			maximumAmountOfJudgmentsForGrade = cumulatedAmountOfJudgmentsForGrade
+		}
+	}
+	if 0 == maximumAmountOfJudgmentsForGrade {
+		maximumAmountOfJudgmentsForGrade = 1
+	}
+
+	const margin = 20
+	const titleHeight = svgFontSize * 4
+	const chartHeight = 400
+	const gradesLabelsHeight = svgFontSize * 2
+	const amountOfTicks = 5
+
+	width := getPixelsWidth(options)
+	axisWidth := (countDigits(int(float64(maximumAmountOfJudgmentsForGrade)/options.Scale)) + 4) * svgFontSize * 6 / 10
+	chartX := margin + axisWidth
+	chartWidth := width - chartX - margin
+	if chartWidth < 50*len(grades) {
+		chartWidth = 50 * len(grades)
+		width = chartX + chartWidth + margin
+	}
+	columnWidth := float64(chartWidth) / float64(len(grades))
+	barWidth := columnWidth * 0.8541
+
+	legendNames := make([]string, 0, len(proposalsResults))
+	legendColors := make(color.Palette, 0, len(proposalsResults))
+	for _, proposalResult := range proposalsResults {
+		legendNames = append(legendNames, markElectedProposal(
+			proposals[proposalResult.Index], proposalResult.Index, options, electedMark,
+		))
+		legendColors = append(legendColors, palette[proposalResult.Index])
+	}
+	legendY := titleHeight + chartHeight + gradesLabelsHeight + margin
+	legend, legendHeight := makeSvgLegend(legendNames, legendColors, chartX, legendY, chartWidth)
+
+	height := legendY + legendHeight + margin
+
+	svg := startSvg(width, height, makeTitle("Opinion Profile", options))
+
+	// Horizontal grid and amounts of judges
+	for tick := 0; tick <= amountOfTicks; tick++ {
+		y := titleHeight + chartHeight - chartHeight*tick/amountOfTicks
+		amount := float64(maximumAmountOfJudgmentsForGrade) * float64(tick) / amountOfTicks / options.Scale
+		svg += fmt.Sprintf(
+			`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#bbbbbb" stroke-dasharray="2,2"/>`+"\n",
+			chartX, y, chartX+chartWidth, y,
+		)
+		svg += makeSvgText(chartX-svgFontSize/2, y+svgFontSize/3, "end", strconv.FormatFloat(amount, 'f', -1, 64), "")
+	}
+	svg += makeSvgText(margin, titleHeight-svgFontSize, "start", "Judges", "")
+
+	for i := range grades {
+		gradeIndex := i
+		if options.GreenToRed {
+			gradeIndex = len(grades) - 1 - i
+		}
+		x := float64(chartX) + columnWidth*float64(i) + (columnWidth-barWidth)/2
+		y := float64(titleHeight + chartHeight)
+		for _, proposalResult := range proposalsResults {
+			proposalTally := pollTally.Proposals[proposalResult.Index]
+			gradeTally := float64(proposalTally.Tally[gradeIndex])
+			barHeight := float64(chartHeight) * gradeTally / float64(maximumAmountOfJudgmentsForGrade)
+			y -= barHeight
+			svg += makeSvgRect(x, y, barWidth, barHeight, palette[proposalResult.Index], fmt.Sprintf(
+				"%s, %s: %s",
+				proposals[proposalResult.Index],
+				grades[gradeIndex],
+				strconv.FormatFloat(gradeTally/options.Scale, 'f', -1, 64),
+			))
+		}
+		svg += makeSvgText(
+			int(x+barWidth/2), titleHeight+chartHeight+svgFontSize+svgFontSize/2, "middle",
+			truncateString(grades[gradeIndex], int(columnWidth)/(svgFontSize*6/10), '…'),
+			"",
+		)
+	}
+
+	svg += legend
+	svg += endSvg()
+
+	return svg, nil
+}
@@ -0,0 +1,213 @@
+package formatter
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/MieuxVoter/majority-judgment-cli/deliberator"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"github.com/muesli/termenv"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// TemplateFormatter renders a user-defined text/template, whose path is in Options.Template.
+// The template is executed against a TemplateData, and may use the functions of makeTemplateFunctions.
+type TemplateFormatter struct{}
+
+func init() {
+	Register(Registration{
+		Name:        "template",
+		Aliases:     []string{"tmpl"},
+		Description: "your own text/template, given with --template",
+		MediaType:   "text/plain; charset=utf-8",
+		Create:      func() Formatter { return &TemplateFormatter{} },
+	})
+}
+
+// TemplateData is what templates are executed against, the documented data model of the template format
+type TemplateData struct {
+	Proposals      []TemplateProposal // sorted by rank when --sort is used, in the input order otherwise
+	Grades         []TemplateGrade    // from "worst" to "best", or the other way around with --green-to-red
+	AmountOfJudges float64
+	Width          int  // desired width, in characters
+	Colorized      bool // whether the color and bar functions use colors
+	Sorted         bool
+	GreenToRed     bool
+	Method         string // deliberation method, like majority or usual
+	Weighted       bool   // whether each judgment counts as much as the weight of its judge
+	Seats          int    // amount of proposals elected with --winners, or 0
+}
+
+// TemplateGrade is a grade, as seen by templates
+type TemplateGrade struct {
+	Index int    // from 0 for the "worst" grade
+	Name  string // as in the input
+	Color string // hexadecimal, like #00a249
+	Char  string // character of the grade in the text charts
+}
+
+// TemplateGradeTally holds the judgments received by a proposal on a grade
+type TemplateGradeTally struct {
+	Grade      TemplateGrade
+	Amount     float64 // amount of judgments
+	Percentage float64 // of all the judgments of the proposal, from 0 to 100
+}
+
+// TemplateProposal is a proposal and its result, as seen by templates
+type TemplateProposal struct {
+	Index             int    // in the input order, from 0
+	Name              string // as in the input
+	Rank              int    // from 1, proposals may share a rank
+	Score             string // compare scores lexicographically to rank proposals ; numeric unless Method is majority
+	MedianGrade       TemplateGrade
+	SecondMedianGrade TemplateGrade
+	AmountOfJudgments float64
+	Tally             []TemplateGradeTally // in the order of TemplateData.Grades
+	Elected           bool                 // whether the proposal was elected with --winners
+
+	tally *judgment.ProposalTally
+}
+
+// Format the provided results
+func (t *TemplateFormatter) Format(
+	pollTally *judgment.PollTally,
+	result *judgment.PollResult,
+	proposals []string,
+	grades []string,
+	options *Options,
+) (string, error) {
+	if "" == options.Template {
+		return "", fmt.Errorf("the template format requires a template file, like so: --template results.tmpl")
+	}
+
+	tmpl, templateErr := template.New(filepath.Base(options.Template)).
+		Funcs(makeTemplateFunctions(options, len(grades))).
+		ParseFiles(options.Template)
+	if nil != templateErr {
+		return "", templateErr
+	}
+
+	buffer := new(bytes.Buffer)
+	executeErr := tmpl.Execute(buffer, makeTemplateData(pollTally, result, proposals, grades, options))
+	if nil != executeErr {
+		return "", executeErr
+	}
+
+	// The command adds its own trailing newline
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+// makeTemplateData prepares the data model of the templates
+func makeTemplateData(
+	pollTally *judgment.PollTally,
+	result *judgment.PollResult,
+	proposals []string,
+	grades []string,
+	options *Options,
+) *TemplateData {
+	proposalsResults := result.Proposals
+	if options.Sorted {
+		proposalsResults = result.ProposalsSorted
+	}
+
+	palette := judgment.CreateDefaultPalette(len(grades))
+	templateGrades := make([]TemplateGrade, 0, len(grades))
+	for gradeIndex, grade := range grades {
+		templateGrades = append(templateGrades, TemplateGrade{
+			Index: gradeIndex,
+			Name:  grade,
+			Color: judgment.DumpColorHexString(palette[gradeIndex], "#", false),
+			Char:  getCharForIndex(gradeIndex),
+		})
+	}
+	displayedGrades := make([]TemplateGrade, 0, len(grades))
+	for i := range templateGrades {
+		gradeIndex := i
+		if options.GreenToRed {
+			gradeIndex = len(grades) - 1 - i
+		}
+		displayedGrades = append(displayedGrades, templateGrades[gradeIndex])
+	}
+
+	templateProposals := make([]TemplateProposal, 0, len(proposalsResults))
+	for _, proposalResult := range proposalsResults {
+		proposalTally := pollTally.Proposals[proposalResult.Index]
+		amountOfJudgments := proposalTally.CountJudgments()
+		gradesTallies := make([]TemplateGradeTally, 0, len(grades))
+		for _, grade := range displayedGrades {
+			percentage := 0.0
+			if amountOfJudgments > 0 {
+				percentage = 100.0 * float64(proposalTally.Tally[grade.Index]) / float64(amountOfJudgments)
+			}
+			gradesTallies = append(gradesTallies, TemplateGradeTally{
+				Grade:      grade,
+				Amount:     float64(proposalTally.Tally[grade.Index]) / options.Scale,
+				Percentage: percentage,
+			})
+		}
+		templateProposals = append(templateProposals, TemplateProposal{
+			Index:             proposalResult.Index,
+			Name:              proposals[proposalResult.Index],
+			Rank:              proposalResult.Rank,
+			Score:             proposalResult.Score,
+			MedianGrade:       templateGrades[proposalResult.Analysis.MedianGrade],
+			SecondMedianGrade: templateGrades[proposalResult.Analysis.SecondMedianGrade],
+			AmountOfJudgments: float64(amountOfJudgments) / options.Scale,
+			Tally:             gradesTallies,
+			Elected:           isElected(options, proposalResult.Index),
+			tally:             proposalTally,
+		})
+	}
+
+	method := options.Method
+	if "" == method {
+		method = deliberator.MajorityJudgment
+	}
+
+	return &TemplateData{
+		Proposals:      templateProposals,
+		Grades:         displayedGrades,
+		AmountOfJudges: float64(pollTally.AmountOfJudges) / options.Scale,
+		Width:          options.Width,
+		Colorized:      options.Colorized,
+		Sorted:         options.Sorted,
+		GreenToRed:     options.GreenToRed,
+		Method:         method,
+		Weighted:       isWeighted(options),
+		Seats:          countSeats(options),
+	}
+}
+
+// makeTemplateFunctions creates the helpers available to the templates, on top of the builtin ones:
+// - percent 12.5            → "12.50%"
+// - pad 8 "Pizza"           → "   Pizza" ; a negative width pads on the right, longer texts are truncated
+// - color 3 "text"          → "text" colored like the grade of index 3, unless colors are disabled
+// - bar 40 .                → the merit profile of the proposal, like the text format draws it
+func makeTemplateFunctions(options *Options, amountOfGrades int) template.FuncMap {
+	colorProfile := termenv.ColorProfile()
+	palette := judgment.CreateDefaultPalette(amountOfGrades)
+
+	return template.FuncMap{
+		"percent": func(percentage float64) string {
+			return fmt.Sprintf("%.2f%%", percentage)
+		},
+		"pad": func(width int, text string) string {
+			maximumLength := width
+			if maximumLength < 0 {
+				maximumLength = -maximumLength
+			}
+			return fmt.Sprintf("%*s", width, truncateString(text, maximumLength, '…'))
+		},
+		"color": func(gradeIndex int, text string) string {
+			if !options.Colorized || gradeIndex < 0 || gradeIndex >= len(palette) {
+				return text
+			}
+			color := colorProfile.FromColor(palette[gradeIndex])
+			return termenv.String(text).Background(color).Foreground(color).String()
+		},
+		"bar": func(width int, proposal TemplateProposal) string {
+			return makeAsciiMeritProfile(proposal.tally, width, options.Colorized, options.GreenToRed)
+		},
+	}
+}
@@ -16,6 +16,18 @@ const minimumDefinitionLength = 7
 // It displays the proposals with their merit profiles and ranks.
 type TextFormatter struct{}
 
+func init() {
+	Register(Registration{
+		Name:        "text",
+		Aliases:     []string{"txt"},
+		Chart:       "merit",
+		Description: "merit profiles drawn with characters, for the terminal",
+		MediaType:   "text/plain; charset=utf-8",
+		Create:      func() Formatter { return &TextFormatter{} },
+		Fallback:    true,
+	})
+}
+
 // Format the provided results
 func (t *TextFormatter) Format(
 	pollTally *judgment.PollTally,
@@ -24,6 +36,9 @@ func (t *TextFormatter) Format(
 	grades []string,
 	options *Options,
 ) (string, error) {
+	deliberation := getDeliberation(options)
+	winnersNote := makeWinnersNote(options, proposals, electedMark)
+	proposals = markElectedProposals(proposals, options, electedMark)
 	out := ""
 
 	expectedWidth := options.Width
@@ -60,6 +75,51 @@ func (t *TextFormatter) Format(
 		amountOfCharactersForProposal = maximumAmountOfCharactersForProposal
 	}
 
+	var methodColumns []methodColumn
+	amountOfCharactersForMethods := make([]int, 0, 4)
+	if nil != deliberation.Methods && 0 < len(proposalsResults) {
+		methodColumns = makeMethodColumns(deliberation.Methods, grades)
+		for _, column := range methodColumns {
+			amountOfCharacters := measureStringLength(column.Title)
+			for _, proposalResult := range proposalsResults {
+				cell := formatMethodCell(&column, &deliberation.Methods.Proposals[proposalResult.Index])
+				if measureStringLength(cell) > amountOfCharacters {
+					amountOfCharacters = measureStringLength(cell)
+				}
+			}
+			amountOfCharactersForMethods = append(amountOfCharactersForMethods, amountOfCharacters)
+		}
+	}
+
+	var proposalsMargins []proposalMargins
+	amountOfCharactersForLead := measureStringLength("Lead")
+	amountOfCharactersForToWin := measureStringLength("To win")
+	if nil != deliberation.Margins {
+		proposalsMargins = makeProposalsMargins(deliberation.Margins, len(proposals))
+		for _, margins := range proposalsMargins {
+			if measureStringLength(formatMargin(margins.Lead)) > amountOfCharactersForLead {
+				amountOfCharactersForLead = measureStringLength(formatMargin(margins.Lead))
+			}
+			if measureStringLength(formatMargin(margins.ToWin)) > amountOfCharactersForToWin {
+				amountOfCharactersForToWin = measureStringLength(formatMargin(margins.ToWin))
+			}
+		}
+	}
+
+	if (nil != methodColumns || nil != proposalsMargins) && 0 < len(proposalsResults) {
+		header := strings.Repeat(" ", amountOfDigitsForRank+3)
+		if showsScores(options) {
+			header += fmt.Sprintf("%-*s  ", measureStringLength(formatScore(proposalsResults[0].Score)), "Score")
+		}
+		for i, column := range methodColumns {
+			header += fmt.Sprintf("%-*s  ", amountOfCharactersForMethods[i], column.Title)
+		}
+		if nil != proposalsMargins {
+			header += fmt.Sprintf("%-*s  %-*s  ", amountOfCharactersForLead, "Lead", amountOfCharactersForToWin, "To win")
+		}
+		out += strings.TrimRight(header, " ") + "\n"
+	}
+
 	chartWidth := 0
 	tableWidth := 0
 	for _, proposalResult := range proposalsResults {
@@ -67,6 +127,26 @@ func (t *TextFormatter) Format(
 			"#%0"+strconv.Itoa(amountOfDigitsForRank)+"d  ",
 			proposalResult.Rank,
 		)
+		if showsScores(options) {
+			line += formatScore(proposalResult.Score) + "  "
+		}
+		for i, column := range methodColumns {
+			line += fmt.Sprintf(
+				"%-*s  ",
+				amountOfCharactersForMethods[i],
+				formatMethodCell(&column, &deliberation.Methods.Proposals[proposalResult.Index]),
+			)
+		}
+		if nil != proposalsMargins {
+			margins := proposalsMargins[proposalResult.Index]
+			line += fmt.Sprintf(
+				"%-*s  %-*s  ",
+				amountOfCharactersForLead,
+				formatMargin(margins.Lead),
+				amountOfCharactersForToWin,
+				formatMargin(margins.ToWin),
+			)
+		}
 		line += fmt.Sprintf(
 			" %*s ",
 			amountOfCharactersForProposal,
@@ -120,6 +200,40 @@ func (t *TextFormatter) Format(
 	out += "\n"
 	out += makeTextLegend("Legend:", legendDefinitions, tableWidth, expectedWidth)
 
+	if nil != proposalsMargins {
+		out += "\n" + wrapText(marginsNote, expectedWidth, "")
+	}
+
+	if isWeighted(options) {
+		out += "\n" + wrapText(weightedNote, expectedWidth, "")
+	}
+
+	if "" != winnersNote {
+		out += "\n" + wrapText(winnersNote, expectedWidth, "")
+	}
+
+	if nil != deliberation.Bootstrap {
+		out += "\n\n" + strings.TrimRight(makeTextBootstrap(
+			deliberation.Bootstrap,
+			proposalsResults,
+			proposals,
+			grades,
+			amountOfCharactersForProposal,
+		), "\n")
+	}
+
+	if nil != deliberation.Groups {
+		out += "\n\n" + strings.TrimRight(makeTextGroups(
+			deliberation.Groups,
+			result,
+			proposalsResults,
+			proposals,
+			grades,
+			amountOfCharactersForProposal,
+			expectedWidth,
+		), "\n")
+	}
+
 	return out, nil
 }
 
@@ -0,0 +1,186 @@
+package formatter
+
+import (
+	"fmt"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"github.com/muesli/termenv"
+	"strconv"
+	"strings"
+)
+
+// TextComparisonFormatter displays the comparison of two rounds of a poll as a table, for the terminal.
+// Rank and majority grade changes are shown as ▲ when they got better, and ▼ when they got worse.
+type TextComparisonFormatter struct{}
+
+// FormatComparison formats the provided comparison
+func (t *TextComparisonFormatter) FormatComparison(
+	comparison *analysis.Comparison,
+	options *Options,
+) (string, error) {
+	expectedWidth := options.Width
+	if expectedWidth <= 0 {
+		expectedWidth = defaultWidth
+	}
+
+	palette := judgment.CreateDefaultPalette(len(comparison.Grades))
+	colorProfile := termenv.ColorProfile()
+	colorize := func(text string, color termenv.Color) string {
+		if !options.Colorized || nil == color {
+			return text
+		}
+		return termenv.String(text).Foreground(color).String()
+	}
+	better := colorProfile.FromColor(palette[len(palette)-1])
+	worse := colorProfile.FromColor(palette[0])
+
+	formatRank := func(rank int) string {
+		if 0 == rank {
+			return "–"
+		}
+		return "#" + strconv.Itoa(rank)
+	}
+	formatChange := func(change int) (string, termenv.Color) {
+		if change > 0 {
+			return "▲" + strconv.Itoa(change), better
+		} else if change < 0 {
+			return "▼" + strconv.Itoa(-change), worse
+		}
+		return "=", nil
+	}
+
+	const rankHeader = "Rank"
+	const proposalHeader = "Proposal"
+	const gradesHeader = "Majority Grade"
+	const amountOfCharactersForChange = 7 // removed
+
+	formatGrades := func(before int, after int) string {
+		formatGrade := func(gradeIndex int) string {
+			if gradeIndex < 0 {
+				return "–"
+			}
+			return getGradeName(comparison.Grades, gradeIndex)
+		}
+		return formatGrade(before) + " → " + formatGrade(after)
+	}
+
+	amountOfCharactersForProposal := measureStringLength(proposalHeader)
+	maximumAmountOfCharactersForProposal := 30
+	amountOfCharactersForRanks := measureStringLength(rankHeader)
+	amountOfCharactersForGrades := measureStringLength(gradesHeader)
+	for _, proposal := range comparison.Proposals {
+		if measureStringLength(proposal.Name) > amountOfCharactersForProposal {
+			amountOfCharactersForProposal = measureStringLength(proposal.Name)
+		}
+		ranks := formatRank(proposal.RankBefore) + " → " + formatRank(proposal.RankAfter)
+		if measureStringLength(ranks) > amountOfCharactersForRanks {
+			amountOfCharactersForRanks = measureStringLength(ranks)
+		}
+		grades := formatGrades(proposal.MajorityGradeBefore, proposal.MajorityGradeAfter)
+		if measureStringLength(grades) > amountOfCharactersForGrades {
+			amountOfCharactersForGrades = measureStringLength(grades)
+		}
+	}
+	if amountOfCharactersForProposal > maximumAmountOfCharactersForProposal {
+		amountOfCharactersForProposal = maximumAmountOfCharactersForProposal
+	}
+
+	amountOfCharactersForDelta := 4
+	for _, proposal := range comparison.Proposals {
+		for _, delta := range proposal.TallyDelta {
+			deltaLength := measureStringLength(formatDelta(delta)) + 1
+			if deltaLength > amountOfCharactersForDelta {
+				amountOfCharactersForDelta = deltaLength
+			}
+		}
+	}
+
+	out := fmt.Sprintf(
+		"%-*s  %-*s %*s  %-*s  %-*s ",
+		amountOfCharactersForRanks, rankHeader,
+		amountOfCharactersForChange, "",
+		amountOfCharactersForProposal, proposalHeader,
+		amountOfCharactersForGrades, gradesHeader,
+		amountOfCharactersForChange, "",
+	)
+	for gradeIndex := range comparison.Grades {
+		gradeChar := getCharForIndex(gradeIndex)
+		if options.Colorized {
+			color := colorProfile.FromColor(palette[gradeIndex])
+			gradeChar = termenv.String(gradeChar).Background(color).Foreground(color).String()
+		}
+		out += strings.Repeat(" ", amountOfCharactersForDelta-1) + gradeChar
+	}
+	out = strings.TrimRight(out, " ") + "\n"
+
+	tableWidth := 0
+	for _, proposal := range comparison.Proposals {
+		rankChange, rankChangeColor := formatChange(proposal.RankChange)
+		gradeChange, gradeChangeColor := formatChange(proposal.MajorityGradeChange)
+		if analysis.StatusKept != proposal.Status {
+			rankChange, rankChangeColor = proposal.Status, nil
+			gradeChange, gradeChangeColor = "", nil
+		}
+
+		line := fmt.Sprintf(
+			"%-*s  ",
+			amountOfCharactersForRanks,
+			formatRank(proposal.RankBefore)+" → "+formatRank(proposal.RankAfter),
+		)
+		line += colorize(fmt.Sprintf("%-*s", amountOfCharactersForChange, rankChange), rankChangeColor) + " "
+		line += fmt.Sprintf(
+			"%*s  ",
+			amountOfCharactersForProposal,
+			truncateString(proposal.Name, amountOfCharactersForProposal, '…'),
+		)
+		line += fmt.Sprintf(
+			"%-*s  ",
+			amountOfCharactersForGrades,
+			formatGrades(proposal.MajorityGradeBefore, proposal.MajorityGradeAfter),
+		)
+		line += colorize(fmt.Sprintf("%-*s", amountOfCharactersForChange, gradeChange), gradeChangeColor) + " "
+		tableWidth = measureStringLength(line)
+		for _, delta := range proposal.TallyDelta {
+			var deltaColor termenv.Color
+			if delta > 0 {
+				deltaColor = better
+			} else if delta < 0 {
+				deltaColor = worse
+			}
+			line += colorize(fmt.Sprintf("%*s", amountOfCharactersForDelta, formatDelta(delta)), deltaColor)
+		}
+		out += strings.TrimRight(line, " ") + "\n"
+	}
+
+	legendDefinitions := make([]string, 0, len(comparison.Grades))
+	for gradeIndex, gradeName := range comparison.Grades {
+		gradeChar := getCharForIndex(gradeIndex)
+		if options.Colorized {
+			color := colorProfile.FromColor(palette[gradeIndex])
+			gradeChar = termenv.String(gradeChar).Background(color).Foreground(color).String()
+		}
+		legendDefinitions = append(legendDefinitions, gradeChar+"="+gradeName)
+	}
+
+	out += "\n"
+	out += fmt.Sprintf(
+		"Judges: %s → %s\n",
+		strconv.FormatFloat(comparison.AmountOfJudgesBefore, 'f', -1, 64),
+		strconv.FormatFloat(comparison.AmountOfJudgesAfter, 'f', -1, 64),
+	)
+	out += makeTextLegend("Judgments delta per grade:", legendDefinitions, tableWidth, expectedWidth)
+
+	return out, nil
+}
+
+// formatDelta formats a difference of amounts, with its sign
+func formatDelta(delta float64) string {
+	if 0 == delta {
+		return "0"
+	}
+	formatted := strconv.FormatFloat(delta, 'f', -1, 64)
+	if delta > 0 {
+		formatted = "+" + formatted
+	}
+	return formatted
+}
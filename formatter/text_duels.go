@@ -0,0 +1,124 @@
+package formatter
+
+import (
+	"fmt"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"github.com/muesli/termenv"
+	"sort"
+	"strings"
+)
+
+// TextDuelsFormatter displays the duels between all the proposals as a matrix, for the terminal.
+// Each cell tells whether the proposal of the row beats (▲) the proposal of the column or loses (▼) to it,
+// and the majority grade that decided.
+type TextDuelsFormatter struct{}
+
+// FormatDuels formats the provided duels
+func (t *TextDuelsFormatter) FormatDuels(
+	duels *analysis.Duels,
+	options *Options,
+) (string, error) {
+	expectedWidth := options.Width
+	if expectedWidth <= 0 {
+		expectedWidth = defaultWidth
+	}
+
+	palette := judgment.CreateDefaultPalette(len(duels.Grades))
+	colorProfile := termenv.ColorProfile()
+	colorize := func(text string, color termenv.Color) string {
+		if !options.Colorized || nil == color {
+			return text
+		}
+		return termenv.String(text).Foreground(color).String()
+	}
+	better := colorProfile.FromColor(palette[len(palette)-1])
+	worse := colorProfile.FromColor(palette[0])
+
+	const maximumAmountOfCharactersForProposal = 20
+	order := orderDuelingProposals(duels, options.Sorted)
+	labels := make([]string, 0, len(order))
+	for _, i := range order {
+		proposal := duels.Proposals[i]
+		labels = append(labels, truncateString(
+			fmt.Sprintf("#%d %s", proposal.Rank, proposal.Name),
+			maximumAmountOfCharactersForProposal,
+			'…',
+		))
+	}
+
+	cells := make([][]string, len(order))
+	colors := make([][]termenv.Color, len(order))
+	amountOfCharactersForColumn := 1
+	for _, label := range labels {
+		if measureStringLength(label) > amountOfCharactersForColumn {
+			amountOfCharactersForColumn = measureStringLength(label)
+		}
+	}
+	for row, i := range order {
+		cells[row] = make([]string, len(order))
+		colors[row] = make([]termenv.Color, len(order))
+		for column, j := range order {
+			duel := duels.Duels[i][j]
+			cell, color := "·", termenv.Color(nil)
+			if !duel.IsAgainstItself() {
+				cell = "= "
+				if i == duel.Winner {
+					cell, color = "▲ ", better
+				} else if j == duel.Winner {
+					cell, color = "▼ ", worse
+				}
+				cell += getGradeName(duels.Grades, duel.DecidingGrade)
+				if isDecidedByTieBreaking(&duel) {
+					cell += "*"
+				}
+			}
+			cells[row][column] = cell
+			colors[row][column] = color
+			if measureStringLength(cell) > amountOfCharactersForColumn {
+				amountOfCharactersForColumn = measureStringLength(cell)
+			}
+		}
+	}
+
+	out := strings.Repeat(" ", amountOfCharactersForColumn)
+	for _, label := range labels {
+		out += fmt.Sprintf("  %-*s", amountOfCharactersForColumn, label)
+	}
+	out = strings.TrimRight(out, " ") + "\n"
+	for row, label := range labels {
+		line := fmt.Sprintf("%-*s", amountOfCharactersForColumn, label)
+		for column, cell := range cells[row] {
+			line += "  " + colorize(fmt.Sprintf("%-*s", amountOfCharactersForColumn, cell), colors[row][column])
+		}
+		out += strings.TrimRight(line, " ") + "\n"
+	}
+
+	out += "\n" + wrapText(
+		"▲ the proposal of the row beats the proposal of the column, ▼ it loses, = they are tied.  "+
+			"The grade is the majority grade that decided, with a * when the tie-breaking did.",
+		expectedWidth,
+		"",
+	)
+
+	return out, nil
+}
+
+// orderDuelingProposals lists the indices of the proposals in the order to display them
+func orderDuelingProposals(duels *analysis.Duels, sorted bool) []int {
+	order := make([]int, 0, len(duels.Proposals))
+	for i := range duels.Proposals {
+		order = append(order, i)
+	}
+	if sorted {
+		sort.SliceStable(order, func(a, b int) bool {
+			return duels.Proposals[order[a]].Rank < duels.Proposals[order[b]].Rank
+		})
+	}
+	return order
+}
+
+// isDecidedByTieBreaking tells whether the duel was not decided by the majority grades themselves
+func isDecidedByTieBreaking(duel *analysis.Duel) bool {
+	return analysis.DecidedByMajorityGrade != duel.DecidedBy || duel.Step > 1
+}
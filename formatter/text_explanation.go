@@ -0,0 +1,191 @@
+package formatter
+
+import (
+	"fmt"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+	"github.com/acarl005/stripansi"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"github.com/muesli/termenv"
+	"strconv"
+	"strings"
+)
+
+// TextExplanationFormatter justifies the ranking in plain sentences, one paragraph per pair of proposals
+type TextExplanationFormatter struct{}
+
+// FormatExplanation formats the provided explanation
+func (t *TextExplanationFormatter) FormatExplanation(
+	explanation *analysis.Explanation,
+	options *Options,
+) (string, error) {
+	expectedWidth := options.Width
+	if expectedWidth <= 0 {
+		expectedWidth = defaultWidth
+	}
+	const indentation = "   "
+
+	palette := judgment.CreateDefaultPalette(len(explanation.Grades))
+	colorProfile := termenv.ColorProfile()
+	colorizeGrade := func(text string, gradeIndex int) string {
+		if !options.Colorized || gradeIndex < 0 || gradeIndex >= len(palette) {
+			return text
+		}
+		return termenv.String(text).Foreground(colorProfile.FromColor(palette[gradeIndex])).String()
+	}
+
+	if 0 == len(explanation.Decisions) {
+		return "There is nothing to explain, since there are less than two proposals.", nil
+	}
+
+	paragraphs := make([]string, 0, len(explanation.Decisions))
+	for _, decision := range explanation.Decisions {
+		paragraph := fmt.Sprintf(
+			"#%d %s %s #%d %s",
+			decision.Above.Rank,
+			colorizeGrade(decision.Above.Name, decision.Above.MajorityGrade),
+			describeDecisionVerb(&decision),
+			decision.Below.Rank,
+			colorizeGrade(decision.Below.Name, decision.Below.MajorityGrade),
+		)
+		paragraph += "\n" + wrapText(
+			strings.Join(makeExplanationSentences(&decision, explanation.Grades), "  "),
+			expectedWidth-len(indentation),
+			indentation,
+		)
+		paragraphs = append(paragraphs, paragraph)
+	}
+
+	return strings.Join(paragraphs, "\n\n"), nil
+}
+
+// describeDecisionVerb tells how the proposals of the decision relate, in a few words
+func describeDecisionVerb(decision *analysis.Decision) string {
+	if analysis.DecidedByNothing == decision.DecidedBy {
+		return "is tied with"
+	}
+	if analysis.DecidedByMajorityGrade == decision.DecidedBy && 1 == len(decision.Steps) {
+		return "is above"
+	}
+	return "is above, by tie-breaking,"
+}
+
+// makeExplanationSentences justifies the decision in plain sentences, step by step
+func makeExplanationSentences(decision *analysis.Decision, grades []string) []string {
+	above := decision.Above.Name
+	below := decision.Below.Name
+	sentences := make([]string, 0, len(decision.Steps)+1)
+
+	for stepIndex, step := range decision.Steps {
+		prefix := ""
+		if len(decision.Steps) > 1 {
+			prefix = fmt.Sprintf("Step %d: ", stepIndex+1)
+		}
+		isLastStep := stepIndex == len(decision.Steps)-1
+
+		if step.First.MajorityGrade != step.Second.MajorityGrade {
+			sentences = append(sentences, fmt.Sprintf(
+				"%s%s has the majority grade %s, which is better than the majority grade %s of %s.",
+				prefix, above,
+				getGradeName(grades, step.First.MajorityGrade),
+				getGradeName(grades, step.Second.MajorityGrade),
+				below,
+			))
+			continue
+		}
+
+		majorityGrade := getGradeName(grades, step.First.MajorityGrade)
+		if isLastStep && analysis.DecidedBySecondGroup == decision.DecidedBy {
+			sentences = append(sentences, fmt.Sprintf(
+				"%sboth have the majority grade %s, but %s has %s, whereas %s has %s.",
+				prefix, majorityGrade,
+				above, describeSecondGroup(&step.First, grades, "its"),
+				below, describeSecondGroup(&step.Second, grades, "its"),
+			), describeSecondGroupsRule(&step))
+			continue
+		}
+
+		sentences = append(sentences, fmt.Sprintf(
+			"%sboth have the majority grade %s, and %s.",
+			prefix, majorityGrade, describeSecondGroup(&step.First, grades, "their"),
+		))
+		if !isLastStep {
+			sentences = append(sentences, "This is a tie, so the judgments of the majority grade of each "+
+				"are moved into the nearest grade of its second group, and the comparison goes on.")
+		}
+	}
+
+	if analysis.DecidedByNothing == decision.DecidedBy {
+		sentences = append(sentences, fmt.Sprintf(
+			"Every step of the tie-breaking gives them the same result, so they share rank %d.",
+			decision.Below.Rank,
+		))
+	}
+
+	// Sentences starting with a prefix-less "both" need their capital letter
+	for i, sentence := range sentences {
+		if strings.HasPrefix(sentence, "both") {
+			sentences[i] = "B" + strings.TrimPrefix(sentence, "b")
+		}
+	}
+
+	return sentences
+}
+
+// describeSecondGroup describes the biggest group of judgments outside the majority grade
+func describeSecondGroup(step *analysis.StepAnalysis, grades []string, possessive string) string {
+	amount := strconv.FormatFloat(step.SecondGroupSize, 'f', -1, 64) + " judgments"
+	if 1 == step.SecondGroupSize {
+		amount = "1 judgment"
+	}
+	majorityGrade := getGradeName(grades, step.MajorityGrade)
+	if step.SecondGroupSign > 0 {
+		return fmt.Sprintf(
+			"%s above %s (%.2f%%), an adhesion",
+			amount, majorityGrade, step.SecondGroupPercent,
+		)
+	} else if step.SecondGroupSign < 0 {
+		return fmt.Sprintf(
+			"%s below %s (%.2f%%), a contestation",
+			amount, majorityGrade, step.SecondGroupPercent,
+		)
+	}
+	return "all " + possessive + " judgments at " + majorityGrade
+}
+
+// describeSecondGroupsRule tells which rule of the tie-breaking sorted the second groups of the step
+func describeSecondGroupsRule(step *analysis.TieBreakingStep) string {
+	above := step.First.SecondGroupSign
+	below := step.Second.SecondGroupSign
+	switch {
+	case above > 0 && below > 0:
+		return "The bigger adhesion wins."
+	case above < 0 && below < 0:
+		return "The smaller contestation wins."
+	case above > 0 && below < 0:
+		return "An adhesion beats a contestation."
+	case above > 0:
+		return "An adhesion beats having all the judgments at the majority grade."
+	}
+	return "Having all the judgments at the majority grade beats a contestation."
+}
+
+// wrapText wraps the text on spaces so that its lines fit in the width, and indents them
+func wrapText(text string, width int, indentation string) string {
+	lines := make([]string, 0, 4)
+	line := ""
+	for _, word := range strings.Split(text, " ") {
+		candidate := word
+		if "" != line {
+			candidate = line + " " + word
+		}
+		if "" != line && measureStringLength(stripansi.Strip(candidate)) > width {
+			lines = append(lines, indentation+strings.TrimRight(line, " "))
+			candidate = word
+		}
+		line = candidate
+	}
+	if "" != strings.TrimSpace(line) {
+		lines = append(lines, indentation+strings.TrimRight(line, " "))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -12,6 +12,17 @@ import (
 // TextOpinionFormatter formats opinion profiles in ASCII
 type TextOpinionFormatter struct{}
 
+func init() {
+	Register(Registration{
+		Name:        "text",
+		Aliases:     []string{"txt"},
+		Chart:       "opinion",
+		Description: "opinion profile drawn with characters, for the terminal",
+		MediaType:   "text/plain; charset=utf-8",
+		Create:      func() Formatter { return &TextOpinionFormatter{} },
+	})
+}
+
 // Format the provided results
 func (t *TextOpinionFormatter) Format(
 	pollTally *judgment.PollTally,
@@ -20,6 +31,8 @@ func (t *TextOpinionFormatter) Format(
 	grades []string,
 	options *Options,
 ) (string, error) {
+	winnersNote := makeWinnersNote(options, proposals, electedMark)
+	proposals = markElectedProposals(proposals, options, electedMark)
 	out := ""
 
 	expectedWidth := options.Width
@@ -139,6 +152,14 @@ func (t *TextOpinionFormatter) Format(
 	out += "\n"
 	out += makeTextLegend("Legend:", legendDefinitions, tableWidth, expectedWidth)
 
+	if isWeighted(options) {
+		out += "\n" + wrapText(weightedNote, expectedWidth, "")
+	}
+
+	if "" != winnersNote {
+		out += "\n" + wrapText(winnersNote, expectedWidth, "")
+	}
+
 	return out, nil
 }
 
@@ -0,0 +1,57 @@
+package formatter
+
+import (
+	"encoding/json"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"image/color"
+)
+
+// Version of the Vega-Lite schema our specifications comply with
+const vegaLiteSchema = "https://vega.github.io/schema/vega-lite/v5.json"
+
+// vegaLiteSpec is a Vega-Lite specification, sparsely filled.
+// We use generic maps since Vega-Lite's grammar is way too large to be typed here.
+type vegaLiteSpec map[string]interface{}
+
+// startVegaLiteSpec creates a specification with the fields shared by all our charts
+func startVegaLiteSpec(title string, values []map[string]interface{}, options *Options) vegaLiteSpec {
+	return vegaLiteSpec{
+		"$schema":     vegaLiteSchema,
+		"title":       title,
+		"width":       getPixelsWidth(options),
+		"background":  svgBackgroundColor,
+		"data":        map[string]interface{}{"values": values},
+		"description": "Majority Judgment results, generated by mj",
+	}
+}
+
+// dumpVegaLiteSpec serializes the specification, indented so that it may be tweaked by hand
+func dumpVegaLiteSpec(spec vegaLiteSpec) (string, error) {
+	jsonBytes, jsonErr := json.MarshalIndent(spec, "", "  ")
+	if jsonErr != nil {
+		return "", jsonErr
+	}
+
+	return string(jsonBytes), nil
+}
+
+// makeVegaLiteElectedOpacity fades the proposals that were not elected, using the elected field of the data
+func makeVegaLiteElectedOpacity() map[string]interface{} {
+	return map[string]interface{}{
+		"condition": map[string]interface{}{"test": "datum.elected", "value": 1},
+		"value":     0.4,
+	}
+}
+
+// makeVegaLiteColorScale creates a scale mapping each name of the domain to its color
+func makeVegaLiteColorScale(domain []string, colors color.Palette) map[string]interface{} {
+	colorsRange := make([]string, 0, len(colors))
+	for _, c := range colors {
+		colorsRange = append(colorsRange, judgment.DumpColorHexString(c, "#", false))
+	}
+
+	return map[string]interface{}{
+		"domain": domain,
+		"range":  colorsRange,
+	}
+}
@@ -0,0 +1,145 @@
+package formatter
+
+import (
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"image/color"
+	"strconv"
+)
+
+// VegaLiteMeritFormatter creates a Vega-Lite specification of the merit profiles of the proposals,
+// with the data inlined, for notebooks like Jupyter and Observable.
+type VegaLiteMeritFormatter struct{}
+
+func init() {
+	Register(Registration{
+		Name:        "vega-lite",
+		Aliases:     []string{"vegalite", "vl"},
+		Chart:       "merit",
+		Description: "Vega-Lite specification of the merit profiles, for notebooks",
+		MediaType:   "application/json",
+		Create:      func() Formatter { return &VegaLiteMeritFormatter{} },
+	})
+}
+
+// Format the provided results
+func (t *VegaLiteMeritFormatter) Format(
+	pollTally *judgment.PollTally,
+	result *judgment.PollResult,
+	proposals []string,
+	grades []string,
+	options *Options,
+) (string, error) {
+	proposalsResults := result.Proposals
+	if options.Sorted {
+		proposalsResults = result.ProposalsSorted
+	}
+
+	palette := judgment.CreateDefaultPalette(len(grades))
+
+	gradesDomain := make([]string, 0, len(grades))
+	gradesColors := make(color.Palette, 0, len(grades))
+	for i := range grades {
+		gradeIndex := i
+		if options.GreenToRed {
+			gradeIndex = len(grades) - 1 - i
+		}
+		gradesDomain = append(gradesDomain, grades[gradeIndex])
+		gradesColors = append(gradesColors, palette[gradeIndex])
+	}
+
+	proposalsOrder := make([]string, 0, len(proposalsResults))
+	values := make([]map[string]interface{}, 0, len(proposalsResults)*len(grades))
+	for _, proposalResult := range proposalsResults {
+		proposalTally := pollTally.Proposals[proposalResult.Index]
+		amountOfJudgments := proposalTally.CountJudgments()
+		proposalsOrder = append(proposalsOrder, proposals[proposalResult.Index])
+		for i := range grades {
+			gradeIndex := i
+			if options.GreenToRed {
+				gradeIndex = len(grades) - 1 - i
+			}
+			percentage := 0.0
+			if amountOfJudgments > 0 {
+				percentage = 100.0 * float64(proposalTally.Tally[gradeIndex]) / float64(amountOfJudgments)
+			}
+			values = append(values, map[string]interface{}{
+				"proposal":   proposals[proposalResult.Index],
+				"rank":       proposalResult.Rank,
+				"grade":      grades[gradeIndex],
+				"order":      i,
+				"amount":     float64(proposalTally.Tally[gradeIndex]) / options.Scale,
+				"percentage": percentage,
+			})
+			if showsScores(options) {
+				values[len(values)-1]["score"], _ = strconv.ParseFloat(proposalResult.Score, 64)
+			}
+			if countSeats(options) > 0 {
+				values[len(values)-1]["elected"] = isElected(options, proposalResult.Index)
+			}
+		}
+	}
+
+	tooltip := []interface{}{
+		map[string]interface{}{"field": "rank", "type": "quantitative"},
+		map[string]interface{}{"field": "proposal", "type": "nominal"},
+		map[string]interface{}{"field": "grade", "type": "ordinal"},
+		map[string]interface{}{"field": "amount", "type": "quantitative"},
+		map[string]interface{}{"field": "percentage", "type": "quantitative", "format": ".1f"},
+	}
+	if showsScores(options) {
+		scoreTooltip := map[string]interface{}{"field": "score", "type": "quantitative", "format": ".3f"}
+		tooltip = append([]interface{}{tooltip[0], scoreTooltip}, tooltip[1:]...)
+	}
+	if countSeats(options) > 0 {
+		tooltip = append(tooltip, map[string]interface{}{"field": "elected", "type": "nominal"})
+	}
+
+	spec := startVegaLiteSpec(makeTitle("Merit Profiles", options), values, options)
+	spec["encoding"] = map[string]interface{}{
+		"y": map[string]interface{}{
+			"field": "proposal",
+			"type":  "nominal",
+			"sort":  proposalsOrder,
+			"title": nil,
+		},
+	}
+	barsEncoding := map[string]interface{}{
+		"x": map[string]interface{}{
+			"field": "percentage",
+			"type":  "quantitative",
+			"stack": "zero",
+			"scale": map[string]interface{}{"domain": []int{0, 100}},
+			"title": "Judgments (%)",
+		},
+		"color": map[string]interface{}{
+			"field":  "grade",
+			"type":   "ordinal",
+			"scale":  makeVegaLiteColorScale(gradesDomain, gradesColors),
+			"legend": map[string]interface{}{"orient": "bottom", "title": nil},
+		},
+		"order":   map[string]interface{}{"field": "order", "type": "quantitative"},
+		"tooltip": tooltip,
+	}
+	if countSeats(options) > 0 {
+		barsEncoding["opacity"] = makeVegaLiteElectedOpacity()
+	}
+	spec["layer"] = []interface{}{
+		map[string]interface{}{
+			"mark":     "bar",
+			"encoding": barsEncoding,
+		},
+		// Median vertical dashed bar
+		map[string]interface{}{
+			"mark": map[string]interface{}{
+				"type":       "rule",
+				"color":      svgTextColor,
+				"strokeDash": []int{4, 2},
+			},
+			"encoding": map[string]interface{}{
+				"x": map[string]interface{}{"datum": 50, "type": "quantitative"},
+			},
+		},
+	}
+
+	return dumpVegaLiteSpec(spec)
+}
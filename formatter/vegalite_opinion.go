@@ -0,0 +1,117 @@
+package formatter
+
+import (
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"image/color"
+	"strconv"
+)
+
+// VegaLiteOpinionFormatter creates a Vega-Lite specification of the opinion profile of the poll,
+// with the data inlined, for notebooks like Jupyter and Observable.
+type VegaLiteOpinionFormatter struct{}
+
+func init() {
+	Register(Registration{
+		Name:        "vega-lite",
+		Aliases:     []string{"vegalite", "vl"},
+		Chart:       "opinion",
+		Description: "Vega-Lite specification of the opinion profile, for notebooks",
+		MediaType:   "application/json",
+		Create:      func() Formatter { return &VegaLiteOpinionFormatter{} },
+	})
+}
+
+// Format the provided results
+func (t *VegaLiteOpinionFormatter) Format(
+	pollTally *judgment.PollTally,
+	result *judgment.PollResult,
+	proposals []string,
+	grades []string,
+	options *Options,
+) (string, error) {
+	proposalsResults := result.Proposals
+	if options.Sorted {
+		proposalsResults = result.ProposalsSorted
+	}
+
+	palette := judgment.CreateDefaultPalette(len(proposals))
+
+	gradesOrder := make([]string, 0, len(grades))
+	for i := range grades {
+		gradeIndex := i
+		if options.GreenToRed {
+			gradeIndex = len(grades) - 1 - i
+		}
+		gradesOrder = append(gradesOrder, grades[gradeIndex])
+	}
+
+	proposalsDomain := make([]string, 0, len(proposalsResults))
+	proposalsColors := make(color.Palette, 0, len(proposalsResults))
+	values := make([]map[string]interface{}, 0, len(proposalsResults)*len(grades))
+	for i, proposalResult := range proposalsResults {
+		proposalTally := pollTally.Proposals[proposalResult.Index]
+		proposalsDomain = append(proposalsDomain, proposals[proposalResult.Index])
+		proposalsColors = append(proposalsColors, palette[proposalResult.Index])
+		for gradeIndex := range grades {
+			values = append(values, map[string]interface{}{
+				"proposal": proposals[proposalResult.Index],
+				"rank":     proposalResult.Rank,
+				"grade":    grades[gradeIndex],
+				"order":    i,
+				"amount":   float64(proposalTally.Tally[gradeIndex]) / options.Scale,
+			})
+			if showsScores(options) {
+				values[len(values)-1]["score"], _ = strconv.ParseFloat(proposalResult.Score, 64)
+			}
+			if countSeats(options) > 0 {
+				values[len(values)-1]["elected"] = isElected(options, proposalResult.Index)
+			}
+		}
+	}
+
+	tooltip := []interface{}{
+		map[string]interface{}{"field": "rank", "type": "quantitative"},
+		map[string]interface{}{"field": "proposal", "type": "nominal"},
+		map[string]interface{}{"field": "grade", "type": "ordinal"},
+		map[string]interface{}{"field": "amount", "type": "quantitative"},
+	}
+	if showsScores(options) {
+		scoreTooltip := map[string]interface{}{"field": "score", "type": "quantitative", "format": ".3f"}
+		tooltip = append([]interface{}{tooltip[0], scoreTooltip}, tooltip[1:]...)
+	}
+	if countSeats(options) > 0 {
+		tooltip = append(tooltip, map[string]interface{}{"field": "elected", "type": "nominal"})
+	}
+
+	spec := startVegaLiteSpec(makeTitle("Opinion Profile", options), values, options)
+	spec["mark"] = "bar"
+	encoding := map[string]interface{}{
+		"x": map[string]interface{}{
+			"field": "grade",
+			"type":  "ordinal",
+			"sort":  gradesOrder,
+			"title": nil,
+			"axis":  map[string]interface{}{"labelAngle": 0},
+		},
+		"y": map[string]interface{}{
+			"field": "amount",
+			"type":  "quantitative",
+			"stack": "zero",
+			"title": "Judges",
+		},
+		"color": map[string]interface{}{
+			"field":  "proposal",
+			"type":   "nominal",
+			"scale":  makeVegaLiteColorScale(proposalsDomain, proposalsColors),
+			"legend": map[string]interface{}{"orient": "bottom", "title": nil},
+		},
+		"order":   map[string]interface{}{"field": "order", "type": "quantitative"},
+		"tooltip": tooltip,
+	}
+	if countSeats(options) > 0 {
+		encoding["opacity"] = makeVegaLiteElectedOpacity()
+	}
+	spec["encoding"] = encoding
+
+	return dumpVegaLiteSpec(spec)
+}
@@ -0,0 +1,17 @@
+package formatter
+
+// weightedNote tells that the results are weighted, for the formatters that may hold sentences
+const weightedNote = "Results are weighted: each judgment counts as much as the weight of its judge."
+
+// isWeighted tells whether the judges were weighted, in which case the formatters must tell so
+func isWeighted(options *Options) bool {
+	return nil != getDeliberation(options).UnweightedTally
+}
+
+// makeTitle appends " (weighted)" to the title when the judges were weighted
+func makeTitle(title string, options *Options) string {
+	if isWeighted(options) {
+		return title + " (weighted)"
+	}
+	return title
+}
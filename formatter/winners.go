@@ -0,0 +1,147 @@
+package formatter
+
+import (
+	"fmt"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+	"strings"
+)
+
+// Marks the names of the elected proposals
+const electedMark = "✓"
+
+// The bitmap font of the png format only has ASCII characters
+const electedMarkAscii = "*"
+
+// isElected tells whether the proposal was elected, when winners were selected
+func isElected(options *Options, proposalIndex int) bool {
+	winners := getDeliberation(options).Winners
+	return nil != winners && winners.Elected[proposalIndex]
+}
+
+// countSeats is the amount of proposals elected, or 0 when no winners were selected
+func countSeats(options *Options) int {
+	winners := getDeliberation(options).Winners
+	if nil == winners {
+		return 0
+	}
+	return winners.Seats
+}
+
+// markElectedProposals appends the mark to the names of the elected proposals, like "Pizza ✓".
+// The human-readable formatters (text, markdown, html and png) also tell below the results
+// how the proposals were elected, with makeWinnersNote.
+// The proposals are returned as they are when no winners were selected.
+func markElectedProposals(proposals []string, options *Options, mark string) []string {
+	if nil == getDeliberation(options).Winners {
+		return proposals
+	}
+	marked := make([]string, 0, len(proposals))
+	for proposalIndex, proposal := range proposals {
+		marked = append(marked, markElectedProposal(proposal, proposalIndex, options, mark))
+	}
+	return marked
+}
+
+// markElectedProposal appends the mark to the name of the proposal if it was elected
+func markElectedProposal(proposal string, proposalIndex int, options *Options, mark string) string {
+	if isElected(options, proposalIndex) {
+		return proposal + " " + mark
+	}
+	return proposal
+}
+
+// makeWinnersNote tells how many proposals were elected, and how a tie at the cutoff was broken, if any,
+// like "✓ Elected: 2 seats.  Pizza, Chips and Pasta share rank #2 at the cutoff: lottery (seed 42) elected Chips."
+// It is empty when no winners were selected.
+func makeWinnersNote(options *Options, proposals []string, mark string) string {
+	winners := getDeliberation(options).Winners
+	if nil == winners {
+		return ""
+	}
+	seats := "seats"
+	if 1 == winners.Seats {
+		seats = "seat"
+	}
+	note := fmt.Sprintf("%s Elected: %d %s.", mark, winners.Seats, seats)
+	if nil == winners.Tie {
+		return note
+	}
+
+	tieBreak := "proposal order"
+	if analysis.TieBreakLottery == winners.TieBreak {
+		tieBreak = fmt.Sprintf("lottery (seed %d)", winners.Seed)
+	}
+	return fmt.Sprintf(
+		"%s  %s share rank #%d at the cutoff: %s elected %s.",
+		note,
+		joinNames(namesOf(winners.Tie.Proposals, proposals)),
+		winners.Tie.Rank,
+		tieBreak,
+		joinNames(namesOf(winners.Tie.Elected, proposals)),
+	)
+}
+
+// jsonWinners holds the proposals elected from the top of the ranking
+type jsonWinners struct {
+	Seats    int             `json:"seats"`
+	TieBreak string          `json:"tieBreak" yaml:"tieBreak"`
+	Seed     *int64          `json:"seed,omitempty" yaml:"seed,omitempty"` // only with the lottery
+	Elected  []int           `json:"elected"`                              // indices of the elected proposals
+	Tie      *jsonWinnersTie `json:"tie,omitempty" yaml:"tie,omitempty"`
+}
+
+type jsonWinnersTie struct {
+	Rank      int   `json:"rank"`
+	Proposals []int `json:"proposals"` // indices of the tied proposals
+	Seats     int   `json:"seats"`
+	Elected   []int `json:"elected"` // indices of the tied proposals elected by the tie-break
+}
+
+// makeJsonWinners prepares the winners for JSON and YAML, or nil when there are none
+func makeJsonWinners(winners *analysis.Winners) *jsonWinners {
+	if nil == winners {
+		return nil
+	}
+
+	elected := make([]int, 0, winners.Seats)
+	for proposalIndex, isElected := range winners.Elected {
+		if isElected {
+			elected = append(elected, proposalIndex)
+		}
+	}
+	jsonWinnersValue := &jsonWinners{
+		Seats:    winners.Seats,
+		TieBreak: winners.TieBreak,
+		Elected:  elected,
+	}
+	if analysis.TieBreakLottery == winners.TieBreak {
+		jsonWinnersValue.Seed = &winners.Seed
+	}
+	if nil != winners.Tie {
+		jsonWinnersValue.Tie = &jsonWinnersTie{
+			Rank:      winners.Tie.Rank,
+			Proposals: winners.Tie.Proposals,
+			Seats:     winners.Tie.Seats,
+			Elected:   winners.Tie.Elected,
+		}
+	}
+
+	return jsonWinnersValue
+}
+
+// namesOf lists the names of the proposals of these indices
+func namesOf(proposalsIndices []int, proposals []string) []string {
+	names := make([]string, 0, len(proposalsIndices))
+	for _, proposalIndex := range proposalsIndices {
+		names = append(names, proposals[proposalIndex])
+	}
+	return names
+}
+
+// joinNames joins names for a sentence, like "Pizza, Chips and Pasta"
+func joinNames(names []string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
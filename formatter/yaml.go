@@ -8,6 +8,16 @@ import (
 // YamlFormatter formats the results in YAML
 type YamlFormatter struct{}
 
+func init() {
+	Register(Registration{
+		Name:        "yaml",
+		Aliases:     []string{"yml"},
+		Description: "proposals, grades, tally and results, as YAML",
+		MediaType:   "application/yaml",
+		Create:      func() Formatter { return &YamlFormatter{} },
+	})
+}
+
 // Format the provided results
 func (t *YamlFormatter) Format(
 	tally *judgment.PollTally,
@@ -16,19 +26,37 @@ func (t *YamlFormatter) Format(
 	grades []string,
 	options *Options,
 ) (string, error) {
+	deliberation := getDeliberation(options)
 
 	// Can ignore options.Sorted because it always sends back everything
 
+	method := ""
+	if showsScores(options) {
+		method = options.Method
+	}
+
 	yamlBytes, yamlErr := yaml.Marshal(struct {
 		Proposals []string             `json:"proposals"`
 		Grades    []string             `json:"grades"`
 		Tally     *judgment.PollTally  `json:"tally"`
+		Scale     float64              `json:"scale,omitempty" yaml:"scale,omitempty"` // only when not 1
 		Result    *judgment.PollResult `json:"result"`
+		Method    string               `json:"method,omitempty" yaml:"method,omitempty"` // only when not Majority Judgment
+		Weighted  bool                 `json:"weighted,omitempty" yaml:"weighted,omitempty"`
+		Winners   *jsonWinners         `json:"winners,omitempty" yaml:"winners,omitempty"`
+		// Tally of the judgments counted once each, only when the judges were weighted
+		UnweightedTally *judgment.PollTally `json:"unweightedTally,omitempty" yaml:"unweightedTally,omitempty"`
 	}{
 		Proposals: proposals,
 		Grades:    grades,
 		Tally:     tally,
+		Scale:     makeDocumentScale(options.Scale),
 		Result:    result,
+		Method:    method,
+		Weighted:  isWeighted(options),
+		Winners:   makeJsonWinners(deliberation.Winners),
+
+		UnweightedTally: deliberation.UnweightedTally,
 	})
 
 	if yamlErr != nil {
@@ -2,8 +2,13 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/MieuxVoter/majority-judgment-cli/cmd"
 	"io"
 	"os"
+	"os/exec"
+	"strings"
 	"testing"
 )
 
@@ -68,6 +73,12 @@ var testData = []struct {
 			"example/example12.ssv",
 		},
 	},
+	{
+		name: "Basic usage, example12.csv",
+		args: []string{
+			"example/example12.csv",
+		},
+	},
 	{
 		name: "--sort usage, example.csv",
 		args: []string{
@@ -91,23 +102,371 @@ var testData = []struct {
 			"--sort",
 		},
 	},
+	{
+		name: "Ballots with grades names, example14.csv",
+		args: []string{
+			"example/example14.csv",
+			"--grades",
+			"reject,poor,fair,good,very good,excellent",
+		},
+	},
+	{
+		name: "--input-kind ballots, example15.csv",
+		args: []string{
+			"example/example15.csv",
+			"--input-kind",
+			"ballots",
+		},
+	},
+	{
+		name: "JSON input, example16.json",
+		args: []string{
+			"example/example16.json",
+		},
+	},
+	{
+		name: "YAML input, example17.yml",
+		args: []string{
+			"example/example17.yml",
+		},
+	},
+	{
+		name: "--delimiter and --names-column, example18.csv",
+		args: []string{
+			"example/example18.csv",
+			"--delimiter",
+			";",
+			"--names-column",
+			"yes",
+		},
+	},
+	{
+		name: "--format svg, example.csv",
+		args: []string{
+			"example/example.csv",
+			"--format",
+			"svg",
+			"--chart",
+			"merit",
+		},
+	},
+	{
+		name: "--format svg --chart opinion, example04.csv",
+		args: []string{
+			"example/example04.csv",
+			"--format",
+			"svg",
+			"--chart",
+			"opinion",
+		},
+	},
+	{
+		name: "--format png --output, example.csv",
+		args: []string{
+			"example/example.csv",
+			"--format",
+			"png",
+			"--chart",
+			"merit",
+			"--output",
+			os.DevNull,
+		},
+	},
+	{
+		name: "--format html, example08.csv",
+		args: []string{
+			"example/example08.csv",
+			"--format",
+			"html",
+		},
+	},
+	{
+		name: "--format markdown, example04.csv",
+		args: []string{
+			"example/example04.csv",
+			"--format",
+			"markdown",
+		},
+	},
+	{
+		name: "--format latex, example.csv",
+		args: []string{
+			"example/example.csv",
+			"--sort",
+			"--format",
+			"latex",
+		},
+	},
+	{
+		name: "--format vega-lite, example.csv",
+		args: []string{
+			"example/example.csv",
+			"--format",
+			"vega-lite",
+			"--chart",
+			"merit",
+		},
+	},
+	{
+		name: "--format vega-lite --chart opinion, example04.csv",
+		args: []string{
+			"example/example04.csv",
+			"--format",
+			"vega-lite",
+			"--chart",
+			"opinion",
+		},
+	},
+	{
+		name: "--format plugin, example.csv",
+		args: []string{
+			"example/example.csv",
+			"--plugins-dir",
+			"example/plugins",
+			"--format",
+			"echo",
+		},
+	},
+	{
+		name: "--format template, example.csv",
+		args: []string{
+			"example/example.csv",
+			"--format",
+			"template",
+			"--template",
+			"example/template.tmpl",
+		},
+	},
+	{
+		name: "--method usual, example.csv",
+		args: []string{
+			"example/example.csv",
+			"--method",
+			"usual",
+		},
+	},
+	{
+		name: "--method central --format markdown, example.csv",
+		args: []string{
+			"example/example.csv",
+			"--format",
+			"markdown",
+			"--method",
+			"central",
+		},
+	},
+	{
+		name: "--compare-methods, example14.csv",
+		args: []string{
+			"example/example14.csv",
+			"--grades",
+			"reject,poor,fair,good,very good,excellent",
+			"--compare-methods",
+		},
+	},
+	{
+		name: "--bootstrap, example.csv",
+		args: []string{
+			"example/example.csv",
+			"--bootstrap",
+			"200",
+			"--seed",
+			"42",
+		},
+	},
+	{
+		name: "--margins, example.csv",
+		args: []string{
+			"example/example.csv",
+			"--margins",
+		},
+	},
+	{
+		name: "Fractions, example20.csv",
+		args: []string{
+			"example/example20.csv",
+		},
+	},
+	{
+		name: "Weighted ballots, example21.csv",
+		args: []string{
+			"example/example21.csv",
+			"--grades",
+			"reject,poor,fair,good,very good,excellent",
+			"--weights",
+			"example/strata.csv",
+		},
+		stdout: "Results are weighted: each judgment counts as much as the weight of its judge.",
+	},
+	{
+		name: "--group-by, example22.csv",
+		args: []string{
+			"example/example22.csv",
+			"--grades",
+			"reject,poor,fair,good,very good,excellent",
+			"--group-by",
+			"department",
+		},
+		stdout: "Results by department:",
+	},
+	{
+		name: "--winners, example23.csv",
+		args: []string{
+			"example/example23.csv",
+			"--winners",
+			"2",
+			"--tie-break",
+			"lottery",
+			"--seed",
+			"2",
+		},
+		stdout: "Elected: 2 seats.",
+	},
+	{
+		name: "--winners --tie-break refuse, example23.csv",
+		args: []string{
+			"example/example23.csv",
+			"--winners",
+			"2",
+		},
+		code:   7,
+		stderr: "Tie Error: Bruno, Carla share rank #2 at the cutoff, but only 1 of them may be elected",
+	},
+	{
+		name: "--winners --tie-break lottery, example23.csv",
+		args: []string{
+			"example/example23.csv",
+			"--winners",
+			"2",
+			"--tie-break",
+			"lottery",
+			"--seed",
+			"2",
+			"--format",
+			"csv",
+			"--sort",
+		},
+		stdout: `1,Alice,309220412117514015,good,fair,true
+2,Bruno,222310119413017515,fair,good,false
+2,Carla,222310119413017515,fair,good,true
+4,Damien,209121312018416515,fair,poor,false
+`,
+	},
+	{
+		name: "--winners --tie-break order, example23.csv",
+		args: []string{
+			"example/example23.csv",
+			"--winners",
+			"2",
+			"--tie-break",
+			"order",
+			"--format",
+			"csv",
+			"--sort",
+		},
+		stdout: `1,Alice,309220412117514015,good,fair,true
+2,Bruno,222310119413017515,fair,good,true
+2,Carla,222310119413017515,fair,good,false
+4,Damien,209121312018416515,fair,poor,false
+`,
+	},
+	{
+		name: "--winners --format svg, example23.csv",
+		args: []string{
+			"example/example23.csv",
+			"--winners",
+			"2",
+			"--tie-break",
+			"order",
+			"--format",
+			"svg",
+		},
+		stdout: `font-weight="bold">Bruno ✓</text>`,
+	},
+	{
+		name: "compare, example.csv example19.csv",
+		args: []string{
+			"compare",
+			"example/example.csv",
+			"example/example19.csv",
+		},
+	},
+	{
+		name: "explain, example.csv",
+		args: []string{
+			"explain",
+			"example/example.csv",
+		},
+	},
+	{
+		name: "duels, example.csv",
+		args: []string{
+			"duels",
+			"example/example.csv",
+			"--sort",
+		},
+	},
+}
+
+// mainArgsEnv holds the arguments of the mj command run by runMain, in JSON
+const mainArgsEnv = "MJ_TEST_MAIN_ARGS"
+
+// TestMain runs the mj command itself, instead of the tests, in the processes started by runMain
+func TestMain(m *testing.M) {
+	if argsJson, isMain := os.LookupEnv(mainArgsEnv); isMain {
+		var args []string
+		if err := json.Unmarshal([]byte(argsJson), &args); nil != err {
+			panic(err)
+		}
+		os.Args = append([]string{os.Args[0]}, args...)
+		main()
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// runMain runs the mj command with the arguments in a process of its own,
+// so that its flags start from their defaults and its exit code may be checked
+func runMain(tb testing.TB, args []string) (code int, stdout string, stderr string) {
+	tb.Helper()
+	argsJson, err := json.Marshal(args)
+	if nil != err {
+		tb.Fatal(err)
+	}
+
+	command := exec.Command(os.Args[0])
+	command.Env = append(os.Environ(), mainArgsEnv+"="+string(argsJson))
+	var stdoutBuffer, stderrBuffer bytes.Buffer
+	command.Stdout = &stdoutBuffer
+	command.Stderr = &stderrBuffer
+	err = command.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code = exitErr.ExitCode()
+	} else if nil != err {
+		tb.Fatal(err)
+	}
+
+	return code, stdoutBuffer.String(), stderrBuffer.String()
 }
 
 func TestAll(t *testing.T) {
 	for _, tt := range testData {
 		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runMain(t, tt.args)
 
-			os.Args = []string{os.Args[0]}
-			os.Args = append(os.Args, tt.args...)
-			main()
-
-			// How to do?
-			// 1. Check return code against tt.code
-			// 2. Check stdout against tt.stdout
-			// 3. Check stderr against tt.stderr
-
-			// Idea: go for acceptance testing using BATS instead ?
-			// https://github.com/bats-core/bats-core.git
+			if tt.code != code {
+				t.Errorf("expected the exit code %d, got %d: %s", tt.code, code, stderr)
+			}
+			if !strings.Contains(stdout, tt.stdout) {
+				t.Errorf("expected the output to contain %q, got %q", tt.stdout, stdout)
+			}
+			if "" == tt.stderr && "" != stderr {
+				t.Errorf("expected no errors, got %q", stderr)
+			} else if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("expected the errors to contain %q, got %q", tt.stderr, stderr)
+			}
 		})
 	}
 }
@@ -115,8 +474,12 @@ func TestAll(t *testing.T) {
 func BenchmarkBasicUsage(b *testing.B) {
 
 	for _, tt := range testData {
+		if 0 != tt.code {
+			continue // the command would exit the benchmark
+		}
 		b.Run(tt.name, func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
+				cmd.ResetFlags()
 				os.Args = []string{os.Args[0]}
 				os.Args = append(os.Args, tt.args...)
 
@@ -0,0 +1,39 @@
+package pipeline
+
+import (
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"math/big"
+	"math/rand"
+)
+
+// makeResampler resamples the judges of the poll as they were read, before their tallies were normalized or scaled,
+// so that each resampled poll holds as many judges as the poll itself.  The ballots are resampled when there are any,
+// along with the weights of their judges, and the tallies otherwise.
+// Each resampled poll is then tallied the way the poll was.
+func makeResampler(
+	judgments [][]int,
+	weights []*big.Rat,
+	tallies [][]*big.Rat,
+	grades []string,
+	config *Config,
+) analysis.Resampler {
+	return func(random *rand.Rand) (*judgment.PollTally, error) {
+		var resampledJudgments [][]int
+		var resampledWeights []*big.Rat
+		var resampledTallies [][]*big.Rat
+		if nil != judgments {
+			resampledJudgments, resampledWeights = analysis.ResampleJudges(judgments, weights, random)
+			resampledTallies = tallyJudgments(resampledJudgments, len(tallies), len(grades), resampledWeights)
+		} else {
+			resampledTallies = analysis.ResampleTallies(tallies, random)
+		}
+
+		amountOfJudges := countPollJudges(resampledJudgments, resampledWeights, config)
+		poll, _, _, pollErr := makePollTally(resampledTallies, amountOfJudges, grades, config)
+		if nil != pollErr {
+			return nil, pollErr
+		}
+		return poll, nil
+	}
+}
@@ -0,0 +1,85 @@
+package pipeline
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// deliberateString deliberates the input with the Config, for the tests
+func deliberateString(t *testing.T, input string, config *Config) *Poll {
+	t.Helper()
+	poll, err := Deliberate(strings.NewReader(input), config)
+	if nil != err {
+		t.Fatal(err)
+	}
+	return poll
+}
+
+// winProbabilities lists the probability of rank 1 of each proposal
+func winProbabilities(poll *Poll) []float64 {
+	probabilities := make([]float64, 0, len(poll.Bootstrap.Proposals))
+	for _, proposal := range poll.Bootstrap.Proposals {
+		probabilities = append(probabilities, proposal.WinProbability)
+	}
+	return probabilities
+}
+
+func TestBootstrap(t *testing.T) {
+	tally := `, reject, poor, fair, good, very good, excellent
+Pizza, 3, 2, 1, 4, 4, 2
+Chips, 2, 3, 0, 4, 3, 4
+Pasta, 4, 5, 1, 4, 0, 2
+`
+	config := NewConfig()
+	config.Bootstrap = 200
+	config.Seed = 42
+	config.Workers = 3
+	poll := deliberateString(t, tally, config)
+
+	expected := []float64{0.32, 0.675, 0.005}
+	if !reflect.DeepEqual(expected, winProbabilities(poll)) {
+		t.Errorf("expected the win probabilities %v, got %v", expected, winProbabilities(poll))
+	}
+
+	// The workers share the iterations, but each iteration has its own random number generator
+	config.Workers = 1
+	if !reflect.DeepEqual(expected, winProbabilities(deliberateString(t, tally, config))) {
+		t.Errorf("expected the amount of workers not to change the win probabilities")
+	}
+
+	// The judges are resampled before their tallies are normalized, so that there are still 16 of them
+	config.Normalize = true
+	if !reflect.DeepEqual(expected, winProbabilities(deliberateString(t, tally, config))) {
+		t.Errorf("expected --normalize not to change the win probabilities")
+	}
+}
+
+func TestBootstrapWeightedJudges(t *testing.T) {
+	ballots := `, Pizza, Chips, Pasta, weight
+Alice, good, excellent, reject, 1
+Bob, poor, very good, fair, 1.5
+Camille, very good, good, poor, 1
+Dylan, reject, reject, excellent, 20
+Emma, excellent, poor, reject, 0.5
+Farida, good, good, , 1
+`
+	config := NewConfig()
+	config.Grades = []string{"reject", "poor", "fair", "good", "very good", "excellent"}
+	config.Bootstrap = 200
+	config.Seed = 42
+	config.Workers = 3
+	poll := deliberateString(t, ballots, config)
+
+	// Pasta wins whenever Dylan is drawn, since each judgment of Dylan weighs as much as all the others
+	expected := []float64{0.075, 0.265, 0.66}
+	if !reflect.DeepEqual(expected, winProbabilities(poll)) {
+		t.Errorf("expected the win probabilities %v, got %v", expected, winProbabilities(poll))
+	}
+
+	// Only the proportions of the weights matter
+	hundredfold := strings.NewReplacer(", 1.5\n", ", 150\n", ", 20\n", ", 2000\n", ", 0.5\n", ", 50\n", ", 1\n", ", 100\n")
+	if !reflect.DeepEqual(expected, winProbabilities(deliberateString(t, hundredfold.Replace(ballots), config))) {
+		t.Errorf("expected weights a hundred times bigger not to change the win probabilities")
+	}
+}
@@ -0,0 +1,41 @@
+package pipeline
+
+// ErrorKind tells which step of the pipeline failed
+type ErrorKind int
+
+const (
+	// ConfigurationError means the Config is not usable, like an unknown format
+	ConfigurationError ErrorKind = iota + 1
+	// ReadingError means the input could not be read or parsed
+	ReadingError
+	// BalancingError means the tallies could not be balanced with the default grade
+	BalancingError
+	// DeliberationError means the Majority Judgment could not be resolved
+	DeliberationError
+	// FormattingError means the formatter failed
+	FormattingError
+	// TieError means a tie straddles the cutoff of the winners, and the tie-break refuses to break it
+	TieError
+)
+
+// Error is the error returned by the steps of the pipeline.
+// Its Kind allows callers to react differently, like the CLI does with its exit codes.
+type Error struct {
+	Kind ErrorKind
+	Err  error
+}
+
+// Error is part of the error interface
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap so that errors.Is and errors.As may inspect the cause
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// newError wraps the cause into an Error of the provided kind
+func newError(kind ErrorKind, err error) *Error {
+	return &Error{Kind: kind, Err: err}
+}
@@ -0,0 +1,106 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+	"github.com/MieuxVoter/majority-judgment-cli/reader"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"math/big"
+	"sort"
+)
+
+// Name of the group of the judges whose group cell is empty
+const noGroupName = "(none)"
+
+// deliberateGroups splits the judges by the GroupBy column of the ballots, and deliberates each group apart,
+// the same way as the whole poll, with the same weights.  Groups are sorted by their names.
+func deliberateGroups(
+	ballots *reader.Ballots,
+	weights []*big.Rat,
+	config *Config,
+	pollDeliberator judgment.DeliberatorInterface,
+) (*analysis.Groups, []string, error) {
+	judgments, proposals, grades := ballots.Judgments, ballots.Proposals, ballots.Grades
+	if nil == ballots.Groups || nil == judgments {
+		return nil, nil, newError(ConfigurationError, errors.New("grouping judges is only available with CSV ballots"))
+	}
+	judgesGroups := ballots.Groups
+	if len(judgesGroups) != len(judgments) {
+		return nil, nil, newError(ReadingError, fmt.Errorf(
+			"found %d groups but %d judges", len(judgesGroups), len(judgments),
+		))
+	}
+
+	judgesIndicesByGroup := make(map[string][]int)
+	for judgeIndex, groupName := range judgesGroups {
+		if "" == groupName {
+			groupName = noGroupName
+		}
+		judgesIndicesByGroup[groupName] = append(judgesIndicesByGroup[groupName], judgeIndex)
+	}
+	groupsNames := make([]string, 0, len(judgesIndicesByGroup))
+	for groupName := range judgesIndicesByGroup {
+		groupsNames = append(groupsNames, groupName)
+	}
+	sort.Strings(groupsNames)
+
+	groups := make([]analysis.Group, 0, len(groupsNames))
+	warnings := make([]string, 0)
+	for _, groupName := range groupsNames {
+		groupJudgments := make([][]int, 0, len(judgesIndicesByGroup[groupName]))
+		var groupWeights []*big.Rat
+		for _, judgeIndex := range judgesIndicesByGroup[groupName] {
+			groupJudgments = append(groupJudgments, judgments[judgeIndex])
+			if nil != weights {
+				groupWeights = append(groupWeights, weights[judgeIndex])
+			}
+		}
+
+		round, scaleWarning, groupErr := deliberateJudgments(
+			groupJudgments, groupWeights, proposals, grades, config, pollDeliberator,
+		)
+		if nil != groupErr {
+			return nil, nil, newError(groupErr.Kind, fmt.Errorf("group `%s`: %s", groupName, groupErr.Err))
+		}
+		if "" != scaleWarning {
+			warnings = append(warnings, fmt.Sprintf("group `%s`: %s", groupName, scaleWarning))
+		}
+		groups = append(groups, analysis.Group{
+			Name:           groupName,
+			AmountOfJudges: len(groupJudgments),
+			Round:          round,
+		})
+	}
+
+	return analysis.MakeGroups(config.GroupBy, groups, len(proposals)), warnings, nil
+}
+
+// deliberateJudgments tallies and deliberates the judgments of some judges, weighted when there are weights
+func deliberateJudgments(
+	judgments [][]int,
+	weights []*big.Rat,
+	proposals []string,
+	grades []string,
+	config *Config,
+	pollDeliberator judgment.DeliberatorInterface,
+) (*analysis.Round, string, *Error) {
+	tallies := tallyJudgments(judgments, len(proposals), len(grades), weights)
+	poll, scale, scaleWarning, pollErr := makePollTally(tallies, countJudges(judgments, weights, config), grades, config)
+	if nil != pollErr {
+		return nil, "", pollErr
+	}
+
+	result, deliberationErr := pollDeliberator.Deliberate(poll)
+	if nil != deliberationErr {
+		return nil, "", newError(DeliberationError, deliberationErr)
+	}
+
+	return &analysis.Round{
+		Tally:     poll,
+		Result:    result,
+		Proposals: proposals,
+		Grades:    grades,
+		Scale:     float64(scale),
+	}, scaleWarning, nil
+}
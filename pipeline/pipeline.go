@@ -0,0 +1,373 @@
+// Package pipeline reads a poll, deliberates it with Majority Judgment (or another method), and formats the results.
+// It is what the mj command does, without the command, so that Go programs may use it directly.
+//
+//	config := pipeline.NewConfig()
+//	config.Format = "json"
+//	poll, err := pipeline.Deliberate(input, config)
+//	out, err := pipeline.Format(poll, config)
+package pipeline
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/MieuxVoter/majority-judgment-cli/analysis"
+	"github.com/MieuxVoter/majority-judgment-cli/deliberator"
+	"github.com/MieuxVoter/majority-judgment-cli/formatter"
+	"github.com/MieuxVoter/majority-judgment-cli/reader"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"io"
+	"math/big"
+	"runtime"
+)
+
+// Config holds the settings of the pipeline, that the mj command reads from its flags
+type Config struct {
+	Format         string              // desired format of the output, like text, json, svg…
+	Chart          string              // one of merit, opinion
+	InputFormat    string              // one of auto, csv, json, yaml
+	InputName      string              // name of the input file, used to detect its format ; may be empty
+	InputKind      string              // one of auto, profiles, ballots ; csv only
+	Grades         []string            // names of the grades used in ballots, from worst to best ; may be empty
+	Csv            reader.CsvOptions   // structure of the CSV input, detected when left empty
+	InvertGrades   bool                // if the input grades are from best to worst
+	Normalize      bool                // normalize input to balance proposal participation
+	AmountOfJudges uint64              // amount of judges participating, or 0 to guess it
+	Default        string              // default grade to use when unbalanced: its name, its index, or majority
+	Method         string              // deliberation method, one of majority, usual, typical, central
+	CompareMethods bool                // also compute the results of other voting methods, like approval
+	Approval       string              // lowest grade counted as an approval: its name or its index ; empty for the middle one
+	Margins        bool                // also compute how many judgments would have to change to swap proposals
+	Bootstrap      int                 // amount of polls resampled from the judges to estimate the uncertainty ; 0 to skip it
+	Seed           int64               // of the random number generator of the bootstrap
+	Workers        int                 // amount of parallel workers of the bootstrap ; 0 for one per CPU
+	WeightColumn   string              // name of the column of the weights of the judges in ballots ; may be empty
+	StratumColumn  string              // name of the column of the strata of the judges in ballots ; may be empty
+	StrataWeights  map[string]*big.Rat // weight of each stratum, multiplying the weights of its judges ; may be nil
+	GroupBy        string              // name of the column of the ballots to split the judges by ; empty to skip it
+	Winners        int                 // amount of proposals to elect from the top of the ranking ; 0 to skip it
+	TieBreak       string              // policy breaking a tie at the cutoff of the winners: refuse, lottery or order
+	PluginsDir     string              // where to look for formatter plugins, before the PATH ; may be empty
+	Options        formatter.Options   // options of the formatter ; its Scale is set by Deliberate
+}
+
+// NewConfig creates a Config with the same defaults as the mj command
+func NewConfig() *Config {
+	return &Config{
+		Format:        "text",
+		Chart:         "merit",
+		InputFormat:   "auto",
+		InputKind:     "auto",
+		Default:       "0",
+		Method:        deliberator.MajorityJudgment,
+		TieBreak:      analysis.TieBreakRefuse,
+		WeightColumn:  "weight",
+		StratumColumn: "stratum",
+		Options: formatter.Options{
+			Colorized: true,
+			Scale:     1.0,
+			Terminal:  "x11",
+			Width:     79,
+			MeritBars: true,
+		},
+	}
+}
+
+// Poll is the outcome of the deliberation, ready to be formatted
+type Poll struct {
+	Tally     *judgment.PollTally
+	Result    *judgment.PollResult
+	Proposals []string // in the order they were submitted
+	Grades    []string // from "worst" to "best"
+	Scale     float64  // the tallies were multiplied by it, so that they are integers ; exact unless Warnings tell so
+	Method    string   // deliberation method that resolved the poll
+	Judgments [][]int  // grade indices given by each judge to each proposal, only when the input had ballots
+	// Weight of each judge, in the order of the Judgments, only when the judges were weighted
+	Weights  []*big.Rat
+	Warnings []string // about the input, like tallies that had to be rounded
+	// What was computed along with the results, each only when the Config asked for it,
+	// like Margins or a Bootstrap ; the UnweightedTally is there when the judges were weighted
+	analysis.Deliberation
+}
+
+// Deliberate reads the input and resolves the poll using Majority Judgment, or the method of the Config
+func Deliberate(input io.Reader, config *Config) (*Poll, error) {
+	pollDeliberator, deliberatorErr := deliberator.Create(config.Method)
+	if nil != deliberatorErr {
+		return nil, newError(ConfigurationError, deliberatorErr)
+	}
+
+	inputBytes, errInput := io.ReadAll(input)
+	if errInput != nil {
+		return nil, newError(ReadingError, errInput)
+	}
+
+	tallyReader, readerErr := CreateReader(inputBytes, config)
+	if nil != readerErr {
+		return nil, readerErr
+	}
+
+	ballots, errReader := readBallots(tallyReader, inputBytes, config)
+	if errReader != nil {
+		return nil, newError(ReadingError, errReader)
+	}
+	judgments, tallies, proposals, grades := ballots.Judgments, ballots.Tallies, ballots.Proposals, ballots.Grades
+
+	weights, weightsErr := readJudgesWeights(ballots, config)
+	if nil != weightsErr {
+		return nil, newError(ReadingError, weightsErr)
+	}
+	var unweightedTally *judgment.PollTally
+	if nil != weights {
+		unweightedTally = makeUnweightedTally(judgments, len(proposals), len(grades))
+		tallies = tallyJudgments(judgments, len(proposals), len(grades), weights)
+	} else if nil == tallies && nil != judgments {
+		tallies = tallyJudgments(judgments, len(proposals), len(grades), nil)
+	}
+
+	// Judges who did not judge some proposals are still judges
+	amountOfJudges := countPollJudges(judgments, weights, config)
+	poll, scale, scaleWarning, pollErr := makePollTally(tallies, amountOfJudges, grades, config)
+	if nil != pollErr {
+		return nil, pollErr
+	}
+	precisionScale := float64(scale)
+	warnings := make([]string, 0, 1)
+	if "" != scaleWarning {
+		warnings = append(warnings, scaleWarning)
+	}
+
+	result, deliberationErr := pollDeliberator.Deliberate(poll)
+	if deliberationErr != nil {
+		return nil, newError(DeliberationError, deliberationErr)
+	}
+
+	deliberated := &Poll{
+		Tally:     poll,
+		Result:    result,
+		Proposals: proposals,
+		Grades:    grades,
+		Scale:     precisionScale,
+		Method:    config.Method,
+		Judgments: judgments,
+		Warnings:  warnings,
+		Weights:   weights,
+		// Raw counts of the judgments, so that weighted results may be checked against them
+		Deliberation: analysis.Deliberation{UnweightedTally: unweightedTally},
+	}
+
+	if config.Winners > 0 {
+		winners, winnersErr := analysis.SelectWinners(result, proposals, config.Winners, config.TieBreak, config.Seed)
+		var tieErr *analysis.TieAtCutoffError
+		if errors.As(winnersErr, &tieErr) {
+			return nil, newError(TieError, winnersErr)
+		} else if nil != winnersErr {
+			return nil, newError(ConfigurationError, winnersErr)
+		}
+		deliberated.Winners = winners
+	}
+
+	if "" != config.GroupBy {
+		groups, groupsWarnings, groupsErr := deliberateGroups(ballots, weights, config, pollDeliberator)
+		if nil != groupsErr {
+			return nil, groupsErr
+		}
+		deliberated.Groups = groups
+		deliberated.Warnings = append(deliberated.Warnings, groupsWarnings...)
+	}
+
+	if config.CompareMethods {
+		approvalThreshold, approvalErr := readApprovalThreshold(config.Approval, grades)
+		if nil != approvalErr {
+			return nil, newError(ConfigurationError, approvalErr)
+		}
+		deliberated.Methods = analysis.CompareMethods(&analysis.Round{
+			Tally:     poll,
+			Result:    result,
+			Proposals: proposals,
+			Grades:    grades,
+			Scale:     precisionScale,
+		}, judgments, weights, approvalThreshold)
+	}
+
+	if config.Margins {
+		if !deliberator.IsMajorityJudgment(config.Method) {
+			return nil, newError(ConfigurationError, fmt.Errorf(
+				"margins are only available with the majority method, not `%s`", config.Method,
+			))
+		}
+		margins, marginsErr := analysis.MakeMargins(&analysis.Round{
+			Tally:     poll,
+			Result:    result,
+			Proposals: proposals,
+			Grades:    grades,
+			Scale:     precisionScale,
+		})
+		if nil != marginsErr {
+			return nil, newError(DeliberationError, marginsErr)
+		}
+		deliberated.Margins = margins
+	}
+
+	if config.Bootstrap > 0 {
+		workers := config.Workers
+		if workers <= 0 {
+			workers = runtime.NumCPU()
+		}
+		bootstrap, bootstrapErr := analysis.MakeBootstrap(
+			&analysis.Round{
+				Tally:     poll,
+				Result:    result,
+				Proposals: proposals,
+				Grades:    grades,
+				Scale:     precisionScale,
+			},
+			makeResampler(judgments, weights, tallies, grades, config),
+			pollDeliberator,
+			config.Bootstrap,
+			config.Seed,
+			workers,
+		)
+		if nil != bootstrapErr {
+			return nil, newError(DeliberationError, bootstrapErr)
+		}
+		deliberated.Bootstrap = bootstrap
+	}
+
+	return deliberated, nil
+}
+
+// readBallots reads the input with the reader, along with the weights, strata and groups of the judges
+// when the reader may read them, so that the input is parsed only once
+func readBallots(tallyReader reader.Reader, input []byte, config *Config) (*reader.Ballots, error) {
+	var inputReader io.Reader = bytes.NewReader(input)
+	if ballotsReader, canReadBallots := tallyReader.(reader.BallotsReader); canReadBallots {
+		return ballotsReader.ReadBallots(&inputReader, !config.InvertGrades)
+	}
+
+	judgments, tallies, proposals, grades, err := tallyReader.Read(&inputReader, !config.InvertGrades)
+	if nil != err {
+		return nil, err
+	}
+	return &reader.Ballots{Judgments: judgments, Tallies: tallies, Proposals: proposals, Grades: grades}, nil
+}
+
+// balancePoll fills the missing judgments of each proposal with the default grade: its name, its index, or majority
+func balancePoll(poll *judgment.PollTally, defaultGrade string, grades []string) *Error {
+	var balancerErr error
+	defaultGradeIndex := indexOf(defaultGrade, grades)
+	if -1 == defaultGradeIndex {
+		if "majority" == defaultGrade || "median" == defaultGrade {
+			balancerErr = poll.BalanceWithMedianDefault()
+		} else {
+			defaultGradeNumber, defaultToErr := reader.ReadNumber(defaultGrade)
+			if nil != defaultToErr {
+				return newError(ConfigurationError, fmt.Errorf(
+					"unrecognized default grade `%s`", defaultGrade,
+				))
+			}
+			balancerErr = poll.BalanceWithStaticDefault(uint8(defaultGradeNumber))
+		}
+	} else {
+		balancerErr = poll.BalanceWithStaticDefault(uint8(defaultGradeIndex))
+	}
+	if balancerErr != nil {
+		return newError(BalancingError, balancerErr)
+	}
+	return nil
+}
+
+// Format the deliberated poll using the format and options of the Config
+func Format(poll *Poll, config *Config) (string, error) {
+	outputFormatter, formatterErr := CreateFormatter(config)
+	if nil != formatterErr {
+		return "", formatterErr
+	}
+
+	options := config.Options
+	options.Scale = poll.Scale
+	options.Method = poll.Method
+	options.Deliberation = &poll.Deliberation
+
+	out, formatErr := outputFormatter.Format(
+		poll.Tally,
+		poll.Result,
+		poll.Proposals,
+		poll.Grades,
+		&options,
+	)
+	if formatErr != nil {
+		return "", newError(FormattingError, formatErr)
+	}
+
+	return out, nil
+}
+
+// CreateReader picks the reader for the input, detecting its format and kind when asked to
+func CreateReader(input []byte, config *Config) (reader.Reader, error) {
+	inputFormat := config.InputFormat
+	if "" == inputFormat || "auto" == inputFormat {
+		inputFormat = reader.DetectFormat(config.InputName, string(input))
+	}
+	inputKind := config.InputKind
+	if "csv" == inputFormat && ("" == inputKind || "auto" == inputKind) {
+		inputKind = "profiles"
+		if reader.LooksLikeBallots(string(input), config.Csv) {
+			inputKind = "ballots"
+		}
+	}
+
+	if "csv" == inputFormat {
+		if "profiles" == inputKind {
+			return reader.ProfilesCsvReader{Options: config.Csv}, nil
+		} else if "ballots" == inputKind {
+			return reader.BallotsCsvReader{
+				Grades:        config.Grades,
+				Options:       config.Csv,
+				WeightColumn:  config.WeightColumn,
+				StratumColumn: config.StratumColumn,
+				GroupColumn:   config.GroupBy,
+			}, nil
+		}
+		return nil, newError(ConfigurationError, fmt.Errorf(
+			"input kind `%s` is not supported.  Supported input kinds: auto, profiles, ballots", inputKind,
+		))
+	} else if "json" == inputFormat {
+		return reader.JsonReader{}, nil
+	} else if "yml" == inputFormat || "yaml" == inputFormat {
+		return reader.YamlReader{}, nil
+	}
+
+	return nil, newError(ConfigurationError, fmt.Errorf(
+		"input format `%s` is not supported.  Supported input formats: auto, csv, json, yaml", inputFormat,
+	))
+}
+
+// CreateFormatter picks the formatter for the format and chart of the Config, from the formatters' registry
+func CreateFormatter(config *Config) (formatter.Formatter, error) {
+	format := config.Format
+	if "" == format {
+		format = "text"
+	}
+	outputFormatter, lookupErr := formatter.Lookup(format, config.Chart)
+	if nil != lookupErr {
+		if formatter.IsRegistered(format) {
+			return nil, newError(ConfigurationError, lookupErr)
+		}
+		pluginPath, isPlugin := formatter.FindPlugin(format, config.PluginsDir)
+		if !isPlugin {
+			return nil, newError(ConfigurationError, fmt.Errorf(
+				"%s.  No plugin named mj-format-%s was found either", lookupErr, format,
+			))
+		}
+		outputFormatter = &formatter.PluginFormatter{Name: format, Path: pluginPath}
+	}
+
+	return outputFormatter, nil
+}
+
+// IsBinary tells whether the formatter outputs binary data, like an image, that must be written as is
+func IsBinary(outputFormatter formatter.Formatter) bool {
+	binaryFormatter, isBinary := outputFormatter.(formatter.BinaryFormatter)
+	return isBinary && binaryFormatter.IsBinary()
+}
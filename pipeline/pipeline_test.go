@@ -0,0 +1,106 @@
+package pipeline
+
+import (
+	"encoding/csv"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocumentsKeepTheScale(t *testing.T) {
+	tally := `, reject, poor, fair, good, very good, excellent
+Pizza, 3, 2, 1, 4, 4, 2
+Chips, 2, 3, 0, 4, 3, 4
+Pasta, 4, 5, 1, 4, 1, 1
+`
+	for _, format := range []string{"json", "yaml"} {
+		t.Run(format, func(t *testing.T) {
+			config := NewConfig()
+			config.Normalize = true
+			config.Format = format
+			poll := deliberateString(t, tally, config)
+			if 1 == poll.Scale {
+				t.Fatal("expected the normalized tallies to be scaled")
+			}
+			document, formatErr := Format(poll, config)
+			if nil != formatErr {
+				t.Fatal(formatErr)
+			}
+			if !strings.Contains(document, "scale") {
+				t.Errorf("expected the document to hold the scale, got %s", document)
+			}
+
+			// Read back without normalizing, the document holds the same poll
+			readConfig := NewConfig()
+			readConfig.InputFormat = format
+			readPoll := deliberateString(t, document, readConfig)
+			if poll.Scale != readPoll.Scale {
+				t.Errorf("expected the scale %v, got %v", poll.Scale, readPoll.Scale)
+			}
+			if !reflect.DeepEqual(poll.Tally, readPoll.Tally) {
+				t.Errorf("expected the tally %+v, got %+v", poll.Tally, readPoll.Tally)
+			}
+		})
+	}
+}
+
+func TestBallotsColumns(t *testing.T) {
+	config := NewConfig()
+	config.Grades = []string{"poor", "good"}
+	config.GroupBy = "group"
+	poll := deliberateString(t, `, Pizza, Chips, weight, group
+Alice, good, poor, 2, a
+Bob, poor, good, 1, b
+Yuko, poor, good, 0.5, a
+`, config)
+	if 3 != len(poll.Weights) || 0 != poll.Weights[0].Cmp(big.NewRat(2, 1)) || 0 != poll.Weights[2].Cmp(big.NewRat(1, 2)) {
+		t.Errorf("expected the weights of the weight column, got %v", poll.Weights)
+	}
+	if nil == poll.Groups || 2 != len(poll.Groups.Groups) || 2 != poll.Groups.Groups[0].AmountOfJudges {
+		t.Errorf("expected the groups of the group column, got %+v", poll.Groups)
+	}
+
+	// Without a header row, there are no columns of weights, even when a grade is named like them
+	config = NewConfig()
+	config.Grades = []string{"poor", "weight"}
+	poll = deliberateString(t, `weight, poor
+poor, weight
+`, config)
+	if nil != poll.Weights || 2 != len(poll.Judgments) || 2 != len(poll.Proposals) {
+		t.Errorf("expected two judges of two proposals without weights, got %v and %v", poll.Judgments, poll.Weights)
+	}
+
+	// Rows are numbered like in the input, from the header row
+	config = NewConfig()
+	config.Grades = []string{"poor", "good"}
+	_, err := Deliberate(strings.NewReader(`, Pizza, weight
+Alice, good, 1
+Bob, poor,
+`), config)
+	if nil == err || !strings.Contains(err.Error(), "judge on row 3") {
+		t.Errorf("expected an error about the weight on row 3, got %v", err)
+	}
+}
+
+func TestWeightedCsvIsValid(t *testing.T) {
+	config := NewConfig()
+	config.Grades = []string{"poor", "good"}
+	config.Format = "csv"
+	poll := deliberateString(t, `, Pizza, Chips, weight
+Alice, good, poor, 2
+Bob, poor, good, 1
+`, config)
+	document, formatErr := Format(poll, config)
+	if nil != formatErr {
+		t.Fatal(formatErr)
+	}
+
+	records, readErr := csv.NewReader(strings.NewReader(document)).ReadAll()
+	if nil != readErr {
+		t.Fatalf("expected a valid CSV, got %v in %q", readErr, document)
+	}
+	if 3 != len(records) || "Rank" != records[0][0] {
+		t.Errorf("expected the headers and a row per proposal, got %q", records)
+	}
+}
@@ -0,0 +1,254 @@
+package pipeline
+
+import (
+	"fmt"
+	"github.com/MieuxVoter/majority-judgment-cli/reader"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// tallyJudgments counts the judgments received by each proposal on each grade.
+// Judgments of -1 are judgments that were not given, and are not counted.
+// Each judgment counts as much as the weight of its judge, or 1 when there are no weights.
+func tallyJudgments(
+	judgments [][]int,
+	amountOfProposals int,
+	amountOfGrades int,
+	weights []*big.Rat,
+) (tallies [][]*big.Rat) {
+	tallies = make([][]*big.Rat, 0, amountOfProposals)
+	for proposalIndex := 0; proposalIndex < amountOfProposals; proposalIndex++ {
+		proposalTally := make([]*big.Rat, 0, amountOfGrades)
+		for gradeIndex := 0; gradeIndex < amountOfGrades; gradeIndex++ {
+			proposalTally = append(proposalTally, new(big.Rat))
+		}
+		tallies = append(tallies, proposalTally)
+	}
+	one := big.NewRat(1, 1)
+	for judgeIndex, judgesJudgments := range judgments {
+		weight := one
+		if nil != weights {
+			weight = weights[judgeIndex]
+		}
+		for proposalIndex, gradeIndex := range judgesJudgments {
+			if gradeIndex < 0 || gradeIndex >= amountOfGrades || proposalIndex >= amountOfProposals {
+				continue
+			}
+			tallies[proposalIndex][gradeIndex].Add(tallies[proposalIndex][gradeIndex], weight)
+		}
+	}
+
+	return
+}
+
+// normalizeTallies scales each proposal's tally so that it sums to 100, exactly.
+// The tallies are left as they are, so that they may still be resampled.
+func normalizeTallies(tallies [][]*big.Rat) [][]*big.Rat {
+	hundred := big.NewRat(100, 1)
+	normalized := make([][]*big.Rat, 0, len(tallies))
+	for _, proposalTally := range tallies {
+		proposalTotal := new(big.Rat)
+		for _, gradeTally := range proposalTally {
+			proposalTotal.Add(proposalTotal, gradeTally)
+		}
+		factor := big.NewRat(1, 1)
+		if 0 != proposalTotal.Sign() {
+			factor.Quo(hundred, proposalTotal)
+		}
+		normalizedTally := make([]*big.Rat, 0, len(proposalTally))
+		for _, gradeTally := range proposalTally {
+			normalizedTally = append(normalizedTally, new(big.Rat).Mul(gradeTally, factor))
+		}
+		normalized = append(normalized, normalizedTally)
+	}
+	return normalized
+}
+
+// countJudges is the amount of judges of the ballots, or the sum of their weights when they are weighted.
+// It is nil when there are no ballots, or when the tallies are normalized,
+// since the amount of judges must then be guessed from the tallies.
+func countJudges(judgments [][]int, weights []*big.Rat, config *Config) *big.Rat {
+	if nil == judgments || config.Normalize {
+		return nil
+	}
+	if nil != weights {
+		return sumWeights(weights)
+	}
+	return big.NewRat(int64(len(judgments)), 1)
+}
+
+// countPollJudges is the amount of judges of the Config when it is set, or else the one counted by countJudges
+func countPollJudges(judgments [][]int, weights []*big.Rat, config *Config) *big.Rat {
+	if config.AmountOfJudges > 0 {
+		return new(big.Rat).SetUint64(config.AmountOfJudges)
+	}
+	return countJudges(judgments, weights, config)
+}
+
+// makePollTally makes the tally of the poll from the exact tallies:
+// normalized when the Config asks to, scaled into integers, and balanced with the default grade of the Config.
+// The amount of judges is scaled along, or guessed from the tallies when it is nil.
+// Returns the scale, and a warning when the tallies had to be rounded.
+func makePollTally(
+	tallies [][]*big.Rat,
+	amountOfJudges *big.Rat,
+	grades []string,
+	config *Config,
+) (*judgment.PollTally, uint64, string, *Error) {
+	if config.Normalize {
+		tallies = normalizeTallies(tallies)
+	}
+
+	scaledTallies, scaledAmountOfJudges, scale, warning, scaleErr := scaleTallies(tallies, amountOfJudges)
+	if nil != scaleErr {
+		return nil, 0, "", newError(ReadingError, scaleErr)
+	}
+
+	poll := &judgment.PollTally{
+		Proposals: make([]*judgment.ProposalTally, 0, len(scaledTallies)),
+	}
+	for _, scaledTally := range scaledTallies {
+		poll.Proposals = append(poll.Proposals, &judgment.ProposalTally{Tally: scaledTally})
+	}
+	if nil != amountOfJudges {
+		poll.AmountOfJudges = scaledAmountOfJudges
+	} else {
+		poll.GuessAmountOfJudges()
+	}
+
+	if balancerErr := balancePoll(poll, config.Default, grades); nil != balancerErr {
+		return nil, 0, "", balancerErr
+	}
+
+	return poll, scale, warning, nil
+}
+
+// Scaled tallies must be integers that fit, along with their sums, in 64 bits ;
+// we keep to signed integers since some computations on the tallies are made with them.
+var maximumScaledTotal = new(big.Int).SetInt64(math.MaxInt64)
+
+// The scale must be exactly representable by the float64 of Options.Scale
+var maximumScale = new(big.Int).Lsh(big.NewInt(1), 53)
+
+// scaleTallies turns the exact tallies into integers, by multiplying them by their least common denominator,
+// which is the scale.  When that would not fit in 64 bits, rounding is unavoidable:
+// the tallies are rounded to the biggest power of ten that fits, and a warning tells so.
+// When even the integer parts of the tallies do not fit, it fails.
+// The amount of judges, when it is known and not an integer, like a sum of weights, is scaled along.
+func scaleTallies(
+	tallies [][]*big.Rat,
+	amountOfJudges *big.Rat,
+) (scaled [][]uint64, scaledAmountOfJudges uint64, scale uint64, warning string, err error) {
+	if 0 == len(tallies) {
+		return [][]uint64{}, 0, 1, "", nil
+	}
+
+	commonDenominator := big.NewInt(1)
+	maximumTotal := new(big.Rat)
+	if nil != amountOfJudges {
+		commonDenominator.Set(amountOfJudges.Denom())
+		maximumTotal.Set(amountOfJudges)
+	}
+	for _, proposalTally := range tallies {
+		proposalTotal := new(big.Rat)
+		for _, gradeTally := range proposalTally {
+			proposalTotal.Add(proposalTotal, gradeTally)
+			denominator := gradeTally.Denom()
+			gcd := new(big.Int).GCD(nil, nil, commonDenominator, denominator)
+			commonDenominator.Mul(commonDenominator, new(big.Int).Quo(denominator, gcd))
+		}
+		if proposalTotal.Cmp(maximumTotal) > 0 {
+			maximumTotal = proposalTotal
+		}
+	}
+
+	fits := func(scale *big.Int) bool {
+		if scale.Cmp(maximumScale) > 0 {
+			return false
+		}
+		scaledTotal := new(big.Rat).Mul(maximumTotal, new(big.Rat).SetInt(scale))
+		// Rounding may add up to a half per grade
+		return new(big.Rat).Add(scaledTotal, big.NewRat(int64(len(tallies[0])), 1)).
+			Cmp(new(big.Rat).SetInt(maximumScaledTotal)) <= 0
+	}
+
+	scaleAsInt := commonDenominator
+	if !fits(commonDenominator) {
+		scaleAsInt = big.NewInt(1)
+		if !fits(scaleAsInt) {
+			err = fmt.Errorf("the tallies are too big, their sums must fit in 64 bits")
+			return
+		}
+		decimals := 0
+		for {
+			nextScale := new(big.Int).Mul(scaleAsInt, big.NewInt(10))
+			if !fits(nextScale) || nextScale.Cmp(commonDenominator) >= 0 {
+				break
+			}
+			scaleAsInt = nextScale
+			decimals++
+		}
+		warning = fmt.Sprintf(
+			"the tallies were rounded to %d decimals, since their common denominator %s is too big",
+			decimals, commonDenominator.String(),
+		)
+	}
+
+	scaleAsRat := new(big.Rat).SetInt(scaleAsInt)
+	scaled = make([][]uint64, 0, len(tallies))
+	for _, proposalTally := range tallies {
+		scaledTally := make([]uint64, 0, len(proposalTally))
+		for _, gradeTally := range proposalTally {
+			scaledGradeTally := new(big.Rat).Mul(gradeTally, scaleAsRat)
+			scaledTally = append(scaledTally, roundRational(scaledGradeTally).Uint64())
+		}
+		scaled = append(scaled, scaledTally)
+	}
+	if nil != amountOfJudges {
+		scaledAmountOfJudges = roundRational(new(big.Rat).Mul(amountOfJudges, scaleAsRat)).Uint64()
+	}
+	scale = scaleAsInt.Uint64()
+
+	return
+}
+
+// roundRational rounds the positive rational to the nearest integer, halves up
+func roundRational(rational *big.Rat) *big.Int {
+	numerator := new(big.Int).Mul(rational.Num(), big.NewInt(2))
+	numerator.Add(numerator, rational.Denom())
+	denominator := new(big.Int).Mul(rational.Denom(), big.NewInt(2))
+	return numerator.Quo(numerator, denominator)
+}
+
+// indexOf searches the data for the element, and returns its index, or -1
+// Go's typing is pretty strict, hence the need for a grunt function like this.
+func indexOf(element string, data []string) int {
+	for k, v := range data {
+		if element == v {
+			return k
+		}
+	}
+	return -1
+}
+
+// readApprovalThreshold reads the lowest grade counted as an approval, by name or by index.
+// Defaults to the middle grade, or the one just above the middle when there is an even amount of grades.
+func readApprovalThreshold(threshold string, grades []string) (int, error) {
+	if "" == strings.TrimSpace(threshold) {
+		return len(grades) / 2, nil
+	}
+	gradeIndex := indexOf(threshold, grades)
+	if -1 != gradeIndex {
+		return gradeIndex, nil
+	}
+	number, numberErr := reader.ReadNumber(threshold)
+	if nil != numberErr || number < 0 || int(number) >= len(grades) || number != float64(int(number)) {
+		return 0, fmt.Errorf(
+			"unrecognized approval threshold `%s`.  Use the name or the index of a grade, like so: --approval 3",
+			threshold,
+		)
+	}
+	return int(number), nil
+}
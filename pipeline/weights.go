@@ -0,0 +1,89 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"github.com/MieuxVoter/majority-judgment-cli/reader"
+	"github.com/mieuxvoter/majority-judgment-library-go/judgment"
+	"math/big"
+)
+
+// readJudgesWeights reads the weight of each judge of the ballots, the weight of their row (or 1)
+// times the weight of their stratum when the Config has StrataWeights.
+// Returns nil when the ballots hold no weights and the Config has no StrataWeights.
+func readJudgesWeights(ballots *reader.Ballots, config *Config) ([]*big.Rat, error) {
+	if nil == ballots.Judgments {
+		if nil != config.StrataWeights {
+			return nil, errors.New("strata weights are only available with ballots")
+		}
+		return nil, nil
+	}
+
+	rowsWeights, strata := ballots.Weights, ballots.Strata
+	amountOfJudges := len(ballots.Judgments)
+	if nil == rowsWeights && nil == config.StrataWeights {
+		return nil, nil
+	}
+	if nil != rowsWeights && len(rowsWeights) != amountOfJudges {
+		return nil, fmt.Errorf("found %d weights but %d judges", len(rowsWeights), amountOfJudges)
+	}
+	if nil != config.StrataWeights {
+		if nil == strata {
+			return nil, errors.New("strata weights were given, but the judges have no stratum")
+		}
+		if len(strata) != amountOfJudges {
+			return nil, fmt.Errorf("found %d strata but %d judges", len(strata), amountOfJudges)
+		}
+	}
+
+	weights := make([]*big.Rat, 0, amountOfJudges)
+	for judgeIndex := 0; judgeIndex < amountOfJudges; judgeIndex++ {
+		weight := big.NewRat(1, 1)
+		if nil != rowsWeights {
+			weight.Set(rowsWeights[judgeIndex])
+		}
+		if nil != config.StrataWeights {
+			stratumWeight, isKnown := config.StrataWeights[strata[judgeIndex]]
+			if !isKnown {
+				return nil, fmt.Errorf(
+					"judge #%d is in stratum `%s`, which has no weight", judgeIndex+1, strata[judgeIndex],
+				)
+			}
+			weight.Mul(weight, stratumWeight)
+		}
+		weights = append(weights, weight)
+	}
+
+	return weights, nil
+}
+
+// sumWeights sums the weights of the judges
+func sumWeights(weights []*big.Rat) *big.Rat {
+	sum := new(big.Rat)
+	for _, weight := range weights {
+		sum.Add(sum, weight)
+	}
+	return sum
+}
+
+// makeUnweightedTally counts the judgments of each proposal on each grade, once each, as integers
+func makeUnweightedTally(judgments [][]int, amountOfProposals int, amountOfGrades int) *judgment.PollTally {
+	pollTally := &judgment.PollTally{
+		AmountOfJudges: uint64(len(judgments)),
+		Proposals:      make([]*judgment.ProposalTally, 0, amountOfProposals),
+	}
+	for proposalIndex := 0; proposalIndex < amountOfProposals; proposalIndex++ {
+		pollTally.Proposals = append(pollTally.Proposals, &judgment.ProposalTally{
+			Tally: make([]uint64, amountOfGrades),
+		})
+	}
+	for _, judgesJudgments := range judgments {
+		for proposalIndex, gradeIndex := range judgesJudgments {
+			if gradeIndex < 0 || gradeIndex >= amountOfGrades || proposalIndex >= amountOfProposals {
+				continue
+			}
+			pollTally.Proposals[proposalIndex].Tally[gradeIndex]++
+		}
+	}
+	return pollTally
+}
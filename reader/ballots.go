@@ -0,0 +1,25 @@
+package reader
+
+import (
+	"io"
+	"math/big"
+)
+
+// Ballots are the judgments of the judges of a poll, along with whatever else the input tells about each judge
+type Ballots struct {
+	Judgments [][]int      // for each judge, the grade index per proposal, or -1
+	Tallies   [][]*big.Rat // for each proposal, the tallies of each grade, when the input holds them too ; may be nil
+	Proposals []string     // in the order they were submitted
+	Grades    []string     // from "worst" to "best"
+	Weights   []*big.Rat   // weight of each judge, or nil when the input holds none
+	Strata    []string     // stratum of each judge, or nil when the input holds none
+	Groups    []string     // group of each judge, or nil when the input is not split into groups
+}
+
+// BallotsReader is a Reader whose input may hold, besides the judgments, the weight, the stratum and the group
+// of each judge, like the columns of a CSV.  They are all read at once, so that the input is parsed only once.
+type BallotsReader interface {
+	Reader
+	// ReadBallots reads the judgments and whatever else the input tells about each judge, in the order of the judges
+	ReadBallots(input *io.Reader, worstGradeToBestGrade bool) (*Ballots, error)
+}
@@ -0,0 +1,341 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// BallotsCsvReader reads the raw ballots of a poll in a CSV, one judge per row, like so:
+//
+//	     , Pizza, Chips,     Pasta
+//	Alice,  good,  poor, excellent
+//	  Bob,  fair,      ,      poor
+//	 Yuko,  poor,  good,      fair
+//
+// Cells hold either the grade's name or its index (0 being the "worst" grade).
+// Empty cells are judgments that were not given, and are read as -1.
+// The first column holds the judges' names, and is optional.
+type BallotsCsvReader struct {
+	// Grades are the names of the grades, in the same order as the grades of the profiles.
+	// When empty, the cells of the ballots must hold grade indices, and the grades' names are generated.
+	Grades []string
+	// Options override the detection of the structure of the CSV
+	Options CsvOptions
+	// WeightColumn is the name, in the header row, of the column holding the weight of each judge, if any
+	WeightColumn string
+	// StratumColumn is the name, in the header row, of the column holding the stratum of each judge, if any
+	StratumColumn string
+	// GroupColumn is the name, in the header row, of the column holding the group of each judge, if any
+	GroupColumn string
+}
+
+// Read the input CSV and return as much data as we can.
+// Read does not fill the `tallies` ; it is up to the caller to tally the `judgments`.
+func (r BallotsCsvReader) Read(
+	input *io.Reader,
+	worstGradeToBestGrade bool,
+) (
+	judgments [][]int,
+	tallies [][]*big.Rat,
+	proposals []string,
+	grades []string,
+	err error,
+) {
+	ballots, errBallots := r.ReadBallots(input, worstGradeToBestGrade)
+	if nil != errBallots {
+		err = errBallots
+		return
+	}
+
+	return ballots.Judgments, nil, ballots.Proposals, ballots.Grades, nil
+}
+
+// ReadBallots reads the input CSV once, into the judgments along with the weight, the stratum and the group
+// of each judge, from their columns.  These columns are found by their names, in the header row, when there is one.
+func (r BallotsCsvReader) ReadBallots(input *io.Reader, worstGradeToBestGrade bool) (*Ballots, error) {
+	// I. Read the rows and cells of the input CSV
+	csvRows, errRows := readCsvRows(input, r.Options)
+	if errRows != nil {
+		return nil, errRows
+	}
+	if 0 == len(csvRows) {
+		return nil, errors.New("no ballots found in input")
+	}
+
+	// II. Detect the shape/structure of the input file, without the columns of weights, strata and groups
+	weightColumn, stratumColumn, groupColumn := r.findJudgesColumns(csvRows[0])
+	rows := removeColumns(csvRows, weightColumn, stratumColumn, groupColumn)
+	hasProposalsNamesRow, hasJudgesNamesColumn := r.detectShape(rows)
+	if !hasProposalsNamesRow {
+		// Without a header row, the cells matching the names of the columns were judgments after all
+		weightColumn, stratumColumn, groupColumn = -1, -1, -1
+		rows = csvRows
+		hasProposalsNamesRow, hasJudgesNamesColumn = r.detectShape(rows)
+	}
+	if "" != strings.TrimSpace(r.GroupColumn) && -1 == groupColumn {
+		return nil, fmt.Errorf("no column named `%s` found in the header row", r.GroupColumn)
+	}
+
+	// III. Read the proposals' names on the first row, or generate some if missing
+	ballots := &Ballots{}
+	amountOfProposals := len(rows[0])
+	if hasJudgesNamesColumn {
+		amountOfProposals--
+	}
+	firstJudgeRow := 0
+	if hasProposalsNamesRow {
+		ballots.Proposals = ReadNamesRow(rows[0], hasJudgesNamesColumn)
+		firstJudgeRow = 1
+	} else {
+		if amountOfProposals > len(alphabet) {
+			return nil, fmt.Errorf("no more than %d proposals can be named (tried %d)", len(alphabet), amountOfProposals)
+		}
+		for j := 0; j < amountOfProposals; j++ {
+			ballots.Proposals = append(ballots.Proposals, "Proposal "+alphabet[j:j+1])
+		}
+	}
+
+	// IV. Read the judgments, with grades indices as they are in the input
+	biggestGradeIndex := len(r.Grades) - 1
+	for rowIndex, row := range rows[firstJudgeRow:] {
+		rowNumber := firstJudgeRow + rowIndex + 1
+		judgesJudgments := make([]int, 0, amountOfProposals)
+		for colIndex, cell := range row {
+			if hasJudgesNamesColumn && 0 == colIndex {
+				continue
+			}
+			gradeIndex, errGrade := r.readGradeIndex(cell)
+			if nil != errGrade {
+				return nil, fmt.Errorf("failed to read the judgment on row %d: %s", rowNumber, errGrade.Error())
+			}
+			if gradeIndex > biggestGradeIndex {
+				biggestGradeIndex = gradeIndex
+			}
+			judgesJudgments = append(judgesJudgments, gradeIndex)
+		}
+		ballots.Judgments = append(ballots.Judgments, judgesJudgments)
+	}
+
+	// V. Read the weight, the stratum and the group of each judge, from the same rows
+	if -1 != groupColumn {
+		ballots.Groups = make([]string, 0, len(ballots.Judgments))
+	}
+	for rowIndex, row := range csvRows[firstJudgeRow:] {
+		rowNumber := firstJudgeRow + rowIndex + 1
+		if -1 != weightColumn {
+			weight, errWeight := readJudgeWeight(row, weightColumn, rowNumber)
+			if nil != errWeight {
+				return nil, errWeight
+			}
+			ballots.Weights = append(ballots.Weights, weight)
+		}
+		if -1 != stratumColumn {
+			ballots.Strata = append(ballots.Strata, readJudgeCell(row, stratumColumn))
+		}
+		if -1 != groupColumn {
+			ballots.Groups = append(ballots.Groups, readJudgeCell(row, groupColumn))
+		}
+	}
+
+	// VI. Read the grades, or generate some if missing
+	if 0 < len(r.Grades) {
+		ballots.Grades = ReadNamesRow(r.Grades, false)
+	} else {
+		var errGradesGen error
+		ballots.Grades, errGradesGen = GenerateDummyGradeNames(biggestGradeIndex + 1)
+		if nil != errGradesGen {
+			return nil, errors.New("Failed to generate default grades names: " + errGradesGen.Error())
+		}
+		if !worstGradeToBestGrade {
+			// Dummy grades are generated from worst to best, but we're going to reverse them below
+			for i, j := 0, len(ballots.Grades)-1; i < j; i, j = i+1, j-1 {
+				ballots.Grades[i], ballots.Grades[j] = ballots.Grades[j], ballots.Grades[i]
+			}
+		}
+	}
+
+	// VII. Flip the grades when they were provided from "best" to "worst"
+	if !worstGradeToBestGrade {
+		//slices.Reverse(grades)
+		grades := ballots.Grades
+		for i, j := 0, len(grades)-1; i < j; i, j = i+1, j-1 {
+			grades[i], grades[j] = grades[j], grades[i]
+		}
+		for _, judgesJudgments := range ballots.Judgments {
+			for proposalIndex, gradeIndex := range judgesJudgments {
+				if gradeIndex >= 0 {
+					judgesJudgments[proposalIndex] = len(grades) - 1 - gradeIndex
+				}
+			}
+		}
+	}
+
+	return ballots, nil
+}
+
+// readGradeIndex reads the grade index of a judgment cell, or -1 if the cell is empty
+func (r BallotsCsvReader) readGradeIndex(cell string) (int, error) {
+	cell = strings.TrimSpace(cell)
+	if "" == cell {
+		return -1, nil
+	}
+
+	for gradeIndex, gradeName := range r.Grades {
+		if strings.EqualFold(cell, strings.TrimSpace(gradeName)) {
+			return gradeIndex, nil
+		}
+	}
+
+	gradeIndex, errIndex := strconv.Atoi(cell)
+	if nil != errIndex {
+		if 0 == len(r.Grades) {
+			return -1, fmt.Errorf("unknown grade `%s` ; please provide the names of the grades", cell)
+		}
+		return -1, fmt.Errorf("unknown grade `%s` ; expected one of %s", cell, strings.Join(r.Grades, ", "))
+	}
+	if gradeIndex < 0 {
+		return -1, fmt.Errorf("strictly negative grade indices are not allowed, but got `%s`", cell)
+	}
+	if 0 < len(r.Grades) && gradeIndex >= len(r.Grades) {
+		return -1, fmt.Errorf("grade index `%s` is too high, there are only %d grades", cell, len(r.Grades))
+	}
+
+	return gradeIndex, nil
+}
+
+// readJudgeWeight reads the weight of the judge of the row, in its column ; the row number is only used in errors
+func readJudgeWeight(row []string, weightColumn int, rowNumber int) (*big.Rat, error) {
+	if weightColumn >= len(row) {
+		return nil, fmt.Errorf("missing the weight of the judge on row %d", rowNumber)
+	}
+	weight, errWeight := ReadRational(row[weightColumn])
+	if "" == strings.TrimSpace(row[weightColumn]) {
+		errWeight = errors.New("it is empty")
+	}
+	if nil != errWeight {
+		return nil, fmt.Errorf("failed to read the weight of the judge on row %d: %s", rowNumber, errWeight)
+	}
+	if weight.Sign() < 0 {
+		return nil, fmt.Errorf("strictly negative weights are not allowed, but got `%s`", row[weightColumn])
+	}
+	return weight, nil
+}
+
+// readJudgeCell reads the cell of the row in that column, or an empty string when the row is too short
+func readJudgeCell(row []string, column int) string {
+	if column >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[column])
+}
+
+// findJudgesColumns finds the columns of the weights, strata and groups by their names in the header row, or -1
+func (r BallotsCsvReader) findJudgesColumns(header []string) (weightColumn int, stratumColumn int, groupColumn int) {
+	weightColumn = r.findColumn(header, r.WeightColumn)
+	stratumColumn = r.findColumn(header, r.StratumColumn)
+	groupColumn = r.findColumn(header, r.GroupColumn)
+	return
+}
+
+// findColumn finds a column by its name in the header row, regardless of case, or -1
+func (r BallotsCsvReader) findColumn(header []string, name string) int {
+	name = strings.TrimSpace(name)
+	if "" == name || r.Options.HasHeader == Absent {
+		return -1
+	}
+	for colIndex, cell := range header {
+		if strings.EqualFold(strings.TrimSpace(cell), name) {
+			return colIndex
+		}
+	}
+	return -1
+}
+
+// removeColumns removes the columns of the rows at these indices, ignoring the indices of -1
+func removeColumns(rows [][]string, columns ...int) [][]string {
+	trimmedRows := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		trimmedRow := make([]string, 0, len(row))
+		for colIndex, cell := range row {
+			isRemoved := false
+			for _, column := range columns {
+				if column == colIndex {
+					isRemoved = true
+				}
+			}
+			if !isRemoved {
+				trimmedRow = append(trimmedRow, cell)
+			}
+		}
+		trimmedRows = append(trimmedRows, trimmedRow)
+	}
+	return trimmedRows
+}
+
+// detectShape gathers metadata about the CSV structure
+func (r BallotsCsvReader) detectShape(rows [][]string) (hasProposalsNamesRow bool, hasJudgesNamesColumn bool) {
+	hasProposalsNamesRow = false
+	hasJudgesNamesColumn = false
+
+	for rowIndex, row := range rows {
+		if rowIndex == 0 {
+			for i := len(row) - 1; i >= 1; i-- {
+				_, errDetection := r.readGradeIndex(row[i])
+				if errDetection != nil {
+					hasProposalsNamesRow = true
+					break
+				}
+			}
+			if hasProposalsNamesRow {
+				if "" == strings.TrimSpace(row[0]) {
+					hasJudgesNamesColumn = true
+				}
+				continue
+			}
+		}
+
+		_, errDetection := r.readGradeIndex(row[0])
+		if errDetection != nil {
+			hasJudgesNamesColumn = true
+		}
+	}
+
+	hasProposalsNamesRow = r.Options.HasHeader.resolve(hasProposalsNamesRow)
+	hasJudgesNamesColumn = r.Options.HasNamesColumn.resolve(hasJudgesNamesColumn)
+
+	return
+}
+
+// LooksLikeBallots tells whether the input CSV seems to hold ballots rather than merit profiles.
+// Merit profiles only hold numbers besides their header row and names column,
+// so any other value found in the cells hints at grades' names, and therefore at ballots.
+func LooksLikeBallots(input string, options CsvOptions) bool {
+	var inputReader io.Reader
+	inputReader = strings.NewReader(input)
+	rows, err := readCsvRows(&inputReader, options)
+	if nil != err {
+		return false
+	}
+
+	skipFirstRow := options.HasHeader.resolve(true)
+	skipFirstColumn := options.HasNamesColumn.resolve(true)
+	for rowIndex, row := range rows {
+		if skipFirstRow && 0 == rowIndex {
+			continue
+		}
+		for colIndex, cell := range row {
+			if skipFirstColumn && 0 == colIndex {
+				continue
+			}
+			if _, errNumber := ReadRational(cell); errNumber != nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,128 @@
+package reader
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/csimplestring/go-csv/detector"
+	"io"
+	"strings"
+)
+
+// Presence of a part of the CSV structure, like a header row or a names column
+type Presence int
+
+const (
+	// Detect the presence from the contents of the CSV
+	Detect Presence = iota
+	// Present for sure, whatever the contents
+	Present
+	// Absent for sure, whatever the contents
+	Absent
+)
+
+// ParsePresence reads a presence from a user-provided string, like "auto", "yes" or "no"
+func ParsePresence(s string) (Presence, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "auto", "detect":
+		return Detect, nil
+	case "yes", "y", "true", "1":
+		return Present, nil
+	case "no", "n", "false", "0":
+		return Absent, nil
+	}
+
+	return Detect, fmt.Errorf("unrecognized presence `%s` ; use one of auto, yes, no", s)
+}
+
+// resolve the presence, using the detected value when there is no override
+func (p Presence) resolve(detected bool) bool {
+	switch p {
+	case Present:
+		return true
+	case Absent:
+		return false
+	}
+
+	return detected
+}
+
+// CsvOptions override the detection of the structure of CSV inputs,
+// for when the detection fails or is ambiguous.
+// The zero value detects everything.
+type CsvOptions struct {
+	Delimiter      rune     // between values ; detected when zero
+	Quote          rune     // around values holding delimiters ; double quote when zero
+	HasHeader      Presence // row of names of grades (profiles) or proposals (ballots)
+	HasNamesColumn Presence // column of names of proposals (profiles) or judges (ballots)
+}
+
+// readCsvRows reads the whole input and splits it into rows of cells.
+// Unless specified in the options, the delimiter between values is detected, and defaults to a space.
+func readCsvRows(input *io.Reader, options CsvOptions) (rows [][]string, err error) {
+	csvDelimiter := ' ' // default value if our detector below fails
+	csvQuote := '"'
+	if 0 != options.Quote {
+		csvQuote = options.Quote
+	}
+
+	// I. Read the whole input at once.  Tried stream reading with io.Pipe but… buffer!
+	allDataBytes, _ := io.ReadAll(*input)
+	allData := sanitizeInput(string(allDataBytes))
+
+	// Go's CSV reader only understands double quotes, so we swap them with the desired quote
+	if '"' != csvQuote {
+		allData = swapRunes(allData, csvQuote, '"')
+	}
+
+	// II. Detect the delimiter between values in the input, unless it is specified
+	if 0 != options.Delimiter {
+		csvDelimiter = options.Delimiter
+	} else {
+		delimiterDetector := detector.New()
+		delimiters := delimiterDetector.DetectDelimiter(strings.NewReader(allData), '"')
+		if 0 < len(delimiters) {
+			csvDelimiter = readFirstRune(delimiters[0])
+		}
+		if 1 < len(delimiters) {
+			err = fmt.Errorf(
+				"too many delimiters: found `%s` and `%s` ; use --delimiter to specify one",
+				delimiters[0], delimiters[1],
+			)
+			return
+		}
+	}
+
+	// III. Read the actual CSV contents
+	csvReader := csv.NewReader(strings.NewReader(allData))
+	csvReader.Comma = csvDelimiter
+	rows, errReader := csvReader.ReadAll()
+	if errReader != nil {
+		err = errors.New("Failed to read input CSV: " + errReader.Error())
+		return
+	}
+
+	// IV. Swap the quotes back in the values
+	if '"' != csvQuote {
+		for _, row := range rows {
+			for i, cell := range row {
+				row[i] = swapRunes(cell, csvQuote, '"')
+			}
+		}
+	}
+
+	return
+}
+
+// swapRunes replaces each rune a by b and each rune b by a
+func swapRunes(str string, a rune, b rune) string {
+	return strings.Map(func(r rune) rune {
+		if r == a {
+			return b
+		}
+		if r == b {
+			return a
+		}
+		return r
+	}, str)
+}
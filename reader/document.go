@@ -0,0 +1,403 @@
+package reader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// readDocument reads the generic structure decoded from a JSON or YAML document,
+// in the shape output by the JSON and YAML formatters:
+//
+//	proposals: [Pizza, Chips, Pasta]
+//	grades: [reject, poor, fair, good, very good, excellent]
+//	tally:
+//	  proposals:
+//	    - tally: [3, 2, 1, 4, 4, 2]
+//	    - tally: [2, 3, 0, 4, 3, 4]
+//	    - tally: [4, 5, 1, 4, 0, 2]
+//
+// The tally may also simply be a list of lists of numbers, one list per proposal.
+// When a `scale` is provided, like the formatters do for tallies they had to scale up to integers,
+// the tally is divided by it.
+// Instead of (or along with) the tally, raw `judgments` may be provided,
+// one list per judge holding a grade (index or name) per proposal, or null when missing.
+// The `result`, if any, is ignored.
+func readDocument(
+	document interface{},
+	worstGradeToBestGrade bool,
+) (
+	judgments [][]int,
+	tallies [][]*big.Rat,
+	proposals []string,
+	grades []string,
+	err error,
+) {
+	root, isMap := asMap(document)
+	if !isMap {
+		err = errors.New("expected a document with `proposals`, `grades` and `tally` properties")
+		return
+	}
+
+	// I. Read the names of the proposals and grades, if any
+	if nil != root["proposals"] {
+		proposals, err = readStrings(root["proposals"], "proposals")
+		if nil != err {
+			return
+		}
+	}
+	if nil != root["grades"] {
+		grades, err = readStrings(root["grades"], "grades")
+		if nil != err {
+			return
+		}
+	}
+
+	// II. Read the tally of each proposal
+	if nil != root["tally"] {
+		tallies, err = readDocumentTally(root["tally"])
+		if nil != err {
+			return
+		}
+	}
+	if nil != root["scale"] && nil != tallies {
+		scale, errScale := readRationalValue(root["scale"])
+		if nil != errScale || scale.Sign() <= 0 {
+			err = fmt.Errorf("expected `scale` to be a strictly positive number, but got `%v`", root["scale"])
+			return
+		}
+		for _, proposalTally := range tallies {
+			for _, gradeTally := range proposalTally {
+				gradeTally.Quo(gradeTally, scale)
+			}
+		}
+	}
+
+	// III. Read the raw judgments of each judge
+	if nil != root["judgments"] {
+		judgments, err = readDocumentJudgments(root["judgments"], proposals, grades)
+		if nil != err {
+			return
+		}
+	}
+
+	if nil == tallies && nil == judgments {
+		err = errors.New("no `tally` nor `judgments` found in input")
+		return
+	}
+
+	// IV. Make sure the amounts of proposals and grades line up
+	amountOfProposals := len(proposals)
+	if nil != tallies {
+		if nil != proposals && len(tallies) != amountOfProposals {
+			err = fmt.Errorf("found %d proposals but %d tallies", amountOfProposals, len(tallies))
+			return
+		}
+		amountOfProposals = len(tallies)
+	}
+	for judgeIndex, judgesJudgments := range judgments {
+		if 0 == amountOfProposals && nil == proposals {
+			amountOfProposals = len(judgesJudgments)
+		}
+		if len(judgesJudgments) != amountOfProposals {
+			err = fmt.Errorf(
+				"judge #%d gave %d judgments but there are %d proposals",
+				judgeIndex+1, len(judgesJudgments), amountOfProposals,
+			)
+			return
+		}
+	}
+
+	amountOfGrades := len(grades)
+	if nil == grades {
+		for _, proposalTally := range tallies {
+			if len(proposalTally) > amountOfGrades {
+				amountOfGrades = len(proposalTally)
+			}
+		}
+		for _, judgesJudgments := range judgments {
+			for _, gradeIndex := range judgesJudgments {
+				if gradeIndex >= amountOfGrades {
+					amountOfGrades = gradeIndex + 1
+				}
+			}
+		}
+	}
+	for proposalIndex, proposalTally := range tallies {
+		if len(proposalTally) != amountOfGrades {
+			err = fmt.Errorf(
+				"the tally of proposal #%d holds %d grades but there are %d grades",
+				proposalIndex+1, len(proposalTally), amountOfGrades,
+			)
+			return
+		}
+	}
+	for judgeIndex, judgesJudgments := range judgments {
+		for _, gradeIndex := range judgesJudgments {
+			if gradeIndex >= amountOfGrades {
+				err = fmt.Errorf(
+					"judge #%d gave grade #%d but there are %d grades",
+					judgeIndex+1, gradeIndex, amountOfGrades,
+				)
+				return
+			}
+		}
+	}
+
+	// V. Generate the missing names
+	if nil == proposals {
+		if amountOfProposals > len(alphabet) {
+			err = fmt.Errorf("no more than %d proposals can be named (tried %d)", len(alphabet), amountOfProposals)
+			return
+		}
+		for j := 0; j < amountOfProposals; j++ {
+			proposals = append(proposals, "Proposal "+alphabet[j:j+1])
+		}
+	}
+	if nil == grades {
+		var errGradesGen error
+		grades, errGradesGen = GenerateDummyGradeNames(amountOfGrades)
+		if nil != errGradesGen {
+			err = errors.New("Failed to generate default grades names: " + errGradesGen.Error())
+			return
+		}
+		if !worstGradeToBestGrade {
+			// Dummy grades are generated from worst to best, but we're going to reverse them below
+			for i, j := 0, len(grades)-1; i < j; i, j = i+1, j-1 {
+				grades[i], grades[j] = grades[j], grades[i]
+			}
+		}
+	}
+
+	// VI. Flip the grades when they were provided from "best" to "worst"
+	if !worstGradeToBestGrade {
+		for i, j := 0, len(grades)-1; i < j; i, j = i+1, j-1 {
+			grades[i], grades[j] = grades[j], grades[i]
+		}
+		for _, proposalTally := range tallies {
+			for i, j := 0, len(proposalTally)-1; i < j; i, j = i+1, j-1 {
+				proposalTally[i], proposalTally[j] = proposalTally[j], proposalTally[i]
+			}
+		}
+		for _, judgesJudgments := range judgments {
+			for proposalIndex, gradeIndex := range judgesJudgments {
+				if gradeIndex >= 0 {
+					judgesJudgments[proposalIndex] = len(grades) - 1 - gradeIndex
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// readDocumentBallots reads the document, along with the optional `weights` and `strata` of its judges
+func readDocumentBallots(document interface{}, worstGradeToBestGrade bool) (*Ballots, error) {
+	judgments, tallies, proposals, grades, err := readDocument(document, worstGradeToBestGrade)
+	if nil != err {
+		return nil, err
+	}
+	weights, strata, err := readDocumentWeights(document)
+	if nil != err {
+		return nil, err
+	}
+
+	return &Ballots{
+		Judgments: judgments,
+		Tallies:   tallies,
+		Proposals: proposals,
+		Grades:    grades,
+		Weights:   weights,
+		Strata:    strata,
+	}, nil
+}
+
+// readDocumentTally reads either a PollTally-like structure or a list of lists of numbers
+func readDocumentTally(tally interface{}) (tallies [][]*big.Rat, err error) {
+	proposalsTallies := tally
+	if tallyMap, isMap := asMap(tally); isMap {
+		proposalsTallies = tallyMap["proposals"]
+	}
+
+	proposalsTalliesList, isList := proposalsTallies.([]interface{})
+	if !isList {
+		err = errors.New("expected `tally` to hold a list of proposals' tallies")
+		return
+	}
+
+	tallies = make([][]*big.Rat, 0, len(proposalsTalliesList))
+	for proposalIndex, proposalTally := range proposalsTalliesList {
+		gradesTallies := proposalTally
+		if proposalTallyMap, isMap := asMap(proposalTally); isMap {
+			gradesTallies = proposalTallyMap["tally"]
+		}
+		gradesTalliesList, isGradesList := gradesTallies.([]interface{})
+		if !isGradesList {
+			err = fmt.Errorf("expected the tally of proposal #%d to be a list of numbers", proposalIndex+1)
+			return
+		}
+
+		proposalTallyOfRationals := make([]*big.Rat, 0, len(gradesTalliesList))
+		for _, gradeTally := range gradesTalliesList {
+			gradeTallyRational, errNumber := readRationalValue(gradeTally)
+			if nil != errNumber {
+				err = fmt.Errorf("failed to read the tally of proposal #%d: %s", proposalIndex+1, errNumber.Error())
+				return
+			}
+			if gradeTallyRational.Sign() < 0 {
+				err = fmt.Errorf("strictly negative numbers are not allowed, but got `%v`", gradeTally)
+				return
+			}
+			proposalTallyOfRationals = append(proposalTallyOfRationals, gradeTallyRational)
+		}
+		tallies = append(tallies, proposalTallyOfRationals)
+	}
+
+	return
+}
+
+// readDocumentJudgments reads a list of judges, each holding a list of judgments (grade index or name, or null).
+// The proposals are only used to name them in errors, and may be nil.
+func readDocumentJudgments(
+	judgmentsValue interface{},
+	proposals []string,
+	grades []string,
+) (judgments [][]int, err error) {
+	judgesList, isList := judgmentsValue.([]interface{})
+	if !isList {
+		err = errors.New("expected `judgments` to hold a list of judges' judgments")
+		return
+	}
+
+	judgments = make([][]int, 0, len(judgesList))
+	for judgeIndex, judgesJudgmentsValue := range judgesList {
+		judgesJudgmentsList, isJudgmentsList := judgesJudgmentsValue.([]interface{})
+		if !isJudgmentsList {
+			err = fmt.Errorf("expected the judgments of judge #%d to be a list", judgeIndex+1)
+			return
+		}
+		judgesJudgments := make([]int, 0, len(judgesJudgmentsList))
+		for proposalIndex, judgmentValue := range judgesJudgmentsList {
+			gradeIndex, errGrade := readGradeValue(judgmentValue, grades)
+			if nil != errGrade {
+				proposal := fmt.Sprintf("proposal #%d", proposalIndex+1)
+				if proposalIndex < len(proposals) {
+					proposal = fmt.Sprintf("proposal `%s`", proposals[proposalIndex])
+				}
+				err = fmt.Errorf(
+					"failed to read the judgment of judge #%d on %s: %s",
+					judgeIndex+1, proposal, errGrade.Error(),
+				)
+				return
+			}
+			judgesJudgments = append(judgesJudgments, gradeIndex)
+		}
+		judgments = append(judgments, judgesJudgments)
+	}
+
+	return
+}
+
+// readGradeValue reads a grade index from its index or name, or -1 when missing.
+// Negative indices are not missing judgments, but mistakes.
+func readGradeValue(value interface{}, grades []string) (int, error) {
+	if nil == value {
+		return -1, nil
+	}
+	if gradeName, isString := value.(string); isString {
+		gradeName = strings.TrimSpace(gradeName)
+		if "" == gradeName {
+			return -1, nil
+		}
+		for gradeIndex, grade := range grades {
+			if strings.EqualFold(gradeName, grade) {
+				return gradeIndex, nil
+			}
+		}
+	}
+
+	gradeFloat, errNumber := readNumberValue(value)
+	if nil != errNumber || gradeFloat != float64(int(gradeFloat)) {
+		return -1, fmt.Errorf("unknown grade `%v`", value)
+	}
+	if gradeFloat < 0 {
+		return -1, fmt.Errorf("strictly negative grade indices are not allowed, but got `%v`", value)
+	}
+
+	return int(gradeFloat), nil
+}
+
+// readStrings reads a list of names
+func readStrings(value interface{}, property string) (names []string, err error) {
+	list, isList := value.([]interface{})
+	if !isList {
+		err = fmt.Errorf("expected `%s` to be a list of names", property)
+		return
+	}
+	names = make([]string, 0, len(list))
+	for _, name := range list {
+		names = append(names, strings.TrimSpace(fmt.Sprint(name)))
+	}
+
+	return
+}
+
+// readNumberValue reads a number from whatever the JSON and YAML decoders provided
+func readNumberValue(value interface{}) (float64, error) {
+	switch number := value.(type) {
+	case json.Number:
+		return number.Float64()
+	case float64:
+		return number, nil
+	case int:
+		return float64(number), nil
+	case uint64:
+		return float64(number), nil
+	case string:
+		return ReadNumber(number)
+	}
+
+	return 0, fmt.Errorf("`%v` is not a number", value)
+}
+
+// readRationalValue reads a number exactly from whatever the JSON and YAML decoders provided.
+// The floats of the YAML decoder are read back from their shortest decimal form, which is what was written.
+func readRationalValue(value interface{}) (*big.Rat, error) {
+	switch number := value.(type) {
+	case json.Number:
+		return ReadRational(number.String())
+	case float64:
+		if math.IsNaN(number) || math.IsInf(number, 0) {
+			break
+		}
+		return ReadRational(strconv.FormatFloat(number, 'g', -1, 64))
+	case int:
+		return new(big.Rat).SetInt64(int64(number)), nil
+	case uint64:
+		return new(big.Rat).SetUint64(number), nil
+	case string:
+		return ReadRational(number)
+	}
+
+	return nil, fmt.Errorf("`%v` is not a number", value)
+}
+
+// asMap casts the decoded mappings of both JSON and YAML into a map of properties
+func asMap(value interface{}) (map[string]interface{}, bool) {
+	valueMap, isMap := value.(map[string]interface{})
+	if !isMap {
+		return nil, false
+	}
+
+	// The YAML formatter lowercases the properties, so we do not care about case
+	properties := make(map[string]interface{}, len(valueMap))
+	for key, property := range valueMap {
+		properties[strings.ToLower(key)] = property
+	}
+
+	return properties, true
+}
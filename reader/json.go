@@ -0,0 +1,52 @@
+package reader
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// JsonReader reads a poll's tally in JSON, in the shape output by the JSON formatter:
+//
+//	{
+//	  "proposals": ["Pizza", "Chips", "Pasta"],
+//	  "grades": ["reject", "poor", "fair", "good", "very good", "excellent"],
+//	  "tally": [[3, 2, 1, 4, 4, 2], [2, 3, 0, 4, 3, 4], [4, 5, 1, 4, 0, 2]]
+//	}
+//
+// See readDocument for the details of the accepted structure.
+type JsonReader struct{}
+
+// Read the input JSON and return as much data as we can.
+func (r JsonReader) Read(
+	input *io.Reader,
+	worstGradeToBestGrade bool,
+) (
+	judgments [][]int,
+	tallies [][]*big.Rat,
+	proposals []string,
+	grades []string,
+	err error,
+) {
+	ballots, errBallots := r.ReadBallots(input, worstGradeToBestGrade)
+	if nil != errBallots {
+		err = errBallots
+		return
+	}
+
+	return ballots.Judgments, ballots.Tallies, ballots.Proposals, ballots.Grades, nil
+}
+
+// ReadBallots reads the input JSON, along with the optional `weights` and `strata` of its judges
+func (r JsonReader) ReadBallots(input *io.Reader, worstGradeToBestGrade bool) (*Ballots, error) {
+	var document interface{}
+	decoder := json.NewDecoder(*input)
+	decoder.UseNumber()
+	errDecode := decoder.Decode(&document)
+	if errDecode != nil {
+		return nil, errors.New("Failed to read input JSON: " + errDecode.Error())
+	}
+
+	return readDocumentBallots(document, worstGradeToBestGrade)
+}
@@ -1,11 +1,9 @@
 package reader
 
 import (
-	"encoding/csv"
 	"errors"
-	"fmt"
-	"github.com/csimplestring/go-csv/detector"
 	"io"
+	"math/big"
 	"strings"
 )
 
@@ -15,7 +13,10 @@ import (
 //	     Pizza, 4, 2, 3, 4, 5, 4, 1
 //	     Chips, 5, 3, 2, 4, 4, 3, 2
 //	     Pasta, 4, 4, 2, 4, 4, 3, 2
-type ProfilesCsvReader struct{}
+type ProfilesCsvReader struct {
+	// Options override the detection of the structure of the CSV
+	Options CsvOptions
+}
 
 // Read the input CSV and return as much data as we can.
 // Read does not fill the `judgments` because this data is absent from the profiles.
@@ -24,37 +25,15 @@ func (r ProfilesCsvReader) Read(
 	worstGradeToBestGrade bool,
 ) (
 	judgments [][]int,
-	tallies [][]float64,
+	tallies [][]*big.Rat,
 	proposals []string,
 	grades []string,
 	err error,
 ) {
-	csvDelimiter := ' ' // default value if our detector below fails
-	csvQuote := '"'
-
-	// I. Read the whole input at once.  Tried stream reading with io.Pipe but… buffer!
-	allDataBytes, _ := io.ReadAll(*input)
-	allData := sanitizeInput(string(allDataBytes))
-	inputReaderForMeta := strings.NewReader(allData)
-	inputReaderForData := strings.NewReader(allData)
-
-	// I.a Detect the delimiter between values in the input
-	delimiterDetector := detector.New()
-	delimiters := delimiterDetector.DetectDelimiter(inputReaderForMeta, byte(csvQuote))
-	if 0 < len(delimiters) {
-		csvDelimiter = readFirstRune(delimiters[0])
-	}
-	if 1 < len(delimiters) {
-		err = fmt.Errorf("too many delimiters: found `%s` and `%s`", delimiters[0], delimiters[1])
-		return
-	}
-
-	// I.b Read the actual CSV contents
-	csvReader := csv.NewReader(inputReaderForData)
-	csvReader.Comma = csvDelimiter
-	csvRows, errReader := csvReader.ReadAll()
-	if errReader != nil {
-		err = errors.New("Failed to read input CSV: " + errReader.Error())
+	// I. Read the rows and cells of the input CSV
+	csvRows, errRows := readCsvRows(input, r.Options)
+	if errRows != nil {
+		err = errRows
 		return
 	}
 
@@ -64,7 +43,7 @@ func (r ProfilesCsvReader) Read(
 	// III. Read the tallies, proposals, grades
 	for rowIndex, row := range csvRows {
 		rowLen := len(row)
-		if rowLen < 2 {
+		if rowLen < 1 || (rowLen < 2 && hasProposalNamesColumn) {
 			continue
 		}
 
@@ -102,18 +81,18 @@ func (r ProfilesCsvReader) Read(
 			}
 
 			// III.c Read the actual tallies
-			proposalTallyOfFloats, tallyErr := ReadTallyRow(row, hasProposalNamesColumn)
+			proposalTallyOfRationals, tallyErr := ReadTallyRow(row, hasProposalNamesColumn)
 			if nil != tallyErr {
 				err = errors.New("Failed to read input tally: " + tallyErr.Error())
 				return
 			}
 			if !worstGradeToBestGrade {
-				//slices.Reverse(proposalTallyOfFloats)
-				for i, j := 0, len(proposalTallyOfFloats)-1; i < j; i, j = i+1, j-1 {
-					proposalTallyOfFloats[i], proposalTallyOfFloats[j] = proposalTallyOfFloats[j], proposalTallyOfFloats[i]
+				//slices.Reverse(proposalTallyOfRationals)
+				for i, j := 0, len(proposalTallyOfRationals)-1; i < j; i, j = i+1, j-1 {
+					proposalTallyOfRationals[i], proposalTallyOfRationals[j] = proposalTallyOfRationals[j], proposalTallyOfRationals[i]
 				}
 			}
-			tallies = append(tallies, proposalTallyOfFloats)
+			tallies = append(tallies, proposalTallyOfRationals)
 		}
 	}
 
@@ -131,7 +110,7 @@ func (r ProfilesCsvReader) detectShape(rows [][]string) (hasGradesNamesRow bool,
 				if "" == strings.TrimSpace(row[i]) {
 					continue
 				}
-				_, errDetection := ReadNumber(row[i])
+				_, errDetection := ReadRational(row[i])
 				if errDetection != nil {
 					hasGradesNamesRow = true
 					break
@@ -143,12 +122,15 @@ func (r ProfilesCsvReader) detectShape(rows [][]string) (hasGradesNamesRow bool,
 			if "" == strings.TrimSpace(row[0]) {
 				continue
 			}
-			_, errDetection := ReadNumber(row[0])
+			_, errDetection := ReadRational(row[0])
 			if errDetection != nil {
 				hasProposalNamesColumn = true
 			}
 		}
 	}
 
+	hasGradesNamesRow = r.Options.HasHeader.resolve(hasGradesNamesRow)
+	hasProposalNamesColumn = r.Options.HasNamesColumn.resolve(hasProposalNamesColumn)
+
 	return
 }
@@ -3,6 +3,8 @@ package reader
 import (
 	"fmt"
 	"io"
+	"math/big"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -18,7 +20,7 @@ type Reader interface {
 		worstGradeToBestGrade bool,
 	) (
 		judgments [][]int, // for each participant, the grade index per proposal, or -1
-		tallies [][]float64, // for each proposal, the tallies of each grade
+		tallies [][]*big.Rat, // for each proposal, the tallies of each grade, exactly
 		proposals []string, // in the order they were submitted
 		grades []string, // from "worst" to "best", just like in tally above
 		err error,
@@ -40,21 +42,21 @@ func sanitizeInput(input string) string {
 	return sanitized
 }
 
-// ReadTallyRow reads a proposal tally row from strings
-func ReadTallyRow(row []string, skipFirst bool) ([]float64, error) {
-	tallies := make([]float64, 0, 7)
+// ReadTallyRow reads a proposal tally row from strings, exactly
+func ReadTallyRow(row []string, skipFirst bool) ([]*big.Rat, error) {
+	tallies := make([]*big.Rat, 0, 7)
 	for colIndex, gradeTally := range row {
 		if skipFirst && colIndex == 0 {
 			continue
 		}
-		gradeTallyFloat, err := ReadNumber(gradeTally)
+		gradeTallyRational, err := ReadRational(gradeTally)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read `%s` as number: %s", gradeTally, err.Error())
 		}
-		if gradeTallyFloat < 0 {
+		if gradeTallyRational.Sign() < 0 {
 			return nil, fmt.Errorf("strictly negative numbers are not allowed, but got `%s`", gradeTally)
 		}
-		tallies = append(tallies, gradeTallyFloat)
+		tallies = append(tallies, gradeTallyRational)
 	}
 
 	return tallies, nil
@@ -82,6 +84,19 @@ func ReadNumber(s string) (n float64, err error) {
 	return strconv.ParseFloat(s, 64)
 }
 
+// ReadRational reads the number from the input string exactly, like 12, 33.3, 1e3 or 100/3
+func ReadRational(s string) (*big.Rat, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return new(big.Rat), nil
+	}
+	rational, isRational := new(big.Rat).SetString(s)
+	if !isRational {
+		return nil, fmt.Errorf("invalid number `%s`", s)
+	}
+	return rational, nil
+}
+
 const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
 
 // GenerateDummyGradeNames generates dummy grade names in reverse alphabetical order
@@ -112,3 +127,26 @@ func readFirstRune(str string) (first rune) {
 	}
 	return
 }
+
+// DetectFormat guesses the format of the input (csv, json or yaml),
+// first from the extension of the file name, and then from its contents.
+func DetectFormat(fileName string, input string) string {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".json":
+		return "json"
+	case ".yml", ".yaml":
+		return "yaml"
+	case ".csv", ".tsv", ".ssv", ".txt":
+		return "csv"
+	}
+
+	trimmed := strings.TrimSpace(input)
+	if strings.HasPrefix(trimmed, "{") {
+		return "json"
+	}
+	if strings.HasPrefix(trimmed, "---") || regexp.MustCompile(`^[A-Za-z_]+:(\s|$)`).MatchString(trimmed) {
+		return "yaml"
+	}
+
+	return "csv"
+}
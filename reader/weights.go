@@ -0,0 +1,88 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+)
+
+// ReadStrataWeights reads the weight of each stratum from a CSV, one stratum per row, like so:
+//
+//	stratum, weight
+//	  urban,    0.8
+//	  rural,    1.4
+//
+// The header row is optional.
+func ReadStrataWeights(input *io.Reader) (map[string]*big.Rat, error) {
+	rows, errRows := readCsvRows(input, CsvOptions{})
+	if nil != errRows {
+		return nil, errRows
+	}
+
+	strataWeights := make(map[string]*big.Rat, len(rows))
+	for rowIndex, row := range rows {
+		if 0 == len(row) || (1 == len(row) && "" == strings.TrimSpace(row[0])) {
+			continue
+		}
+		if 2 != len(row) {
+			return nil, fmt.Errorf("expected a stratum and its weight on row %d, but got %d values", rowIndex+1, len(row))
+		}
+		stratum := strings.TrimSpace(row[0])
+		weight, errWeight := ReadRational(row[1])
+		if nil != errWeight {
+			if 0 == rowIndex {
+				continue // header row
+			}
+			return nil, fmt.Errorf("failed to read the weight of stratum `%s`: %s", stratum, errWeight)
+		}
+		if weight.Sign() < 0 {
+			return nil, fmt.Errorf("strictly negative weights are not allowed, but got `%s`", row[1])
+		}
+		if _, isDuplicate := strataWeights[stratum]; isDuplicate {
+			return nil, fmt.Errorf("stratum `%s` has more than one weight", stratum)
+		}
+		strataWeights[stratum] = weight
+	}
+	if 0 == len(strataWeights) {
+		return nil, errors.New("no strata weights found")
+	}
+
+	return strataWeights, nil
+}
+
+// readDocumentWeights reads the optional `weights` and `strata` lists of a document, one item per judge
+func readDocumentWeights(document interface{}) (weights []*big.Rat, strata []string, err error) {
+	root, isMap := asMap(document)
+	if !isMap {
+		err = errors.New("expected a document with `proposals`, `grades` and `tally` properties")
+		return
+	}
+
+	if nil != root["weights"] {
+		weightsList, isList := root["weights"].([]interface{})
+		if !isList {
+			err = errors.New("expected `weights` to be a list of numbers, one per judge")
+			return
+		}
+		weights = make([]*big.Rat, 0, len(weightsList))
+		for judgeIndex, weightValue := range weightsList {
+			weight, errWeight := readRationalValue(weightValue)
+			if nil != errWeight {
+				err = fmt.Errorf("failed to read the weight of judge #%d: %s", judgeIndex+1, errWeight)
+				return
+			}
+			if weight.Sign() < 0 {
+				err = fmt.Errorf("strictly negative weights are not allowed, but got `%v`", weightValue)
+				return
+			}
+			weights = append(weights, weight)
+		}
+	}
+	if nil != root["strata"] {
+		strata, err = readStrings(root["strata"], "strata")
+	}
+
+	return
+}
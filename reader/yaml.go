@@ -0,0 +1,51 @@
+package reader
+
+import (
+	"errors"
+	"gopkg.in/yaml.v3"
+	"io"
+	"math/big"
+)
+
+// YamlReader reads a poll's tally in YAML, in the shape output by the YAML formatter:
+//
+//	proposals: [Pizza, Chips, Pasta]
+//	grades: [reject, poor, fair, good, very good, excellent]
+//	tally:
+//	  - [3, 2, 1, 4, 4, 2]
+//	  - [2, 3, 0, 4, 3, 4]
+//	  - [4, 5, 1, 4, 0, 2]
+//
+// See readDocument for the details of the accepted structure.
+type YamlReader struct{}
+
+// Read the input YAML and return as much data as we can.
+func (r YamlReader) Read(
+	input *io.Reader,
+	worstGradeToBestGrade bool,
+) (
+	judgments [][]int,
+	tallies [][]*big.Rat,
+	proposals []string,
+	grades []string,
+	err error,
+) {
+	ballots, errBallots := r.ReadBallots(input, worstGradeToBestGrade)
+	if nil != errBallots {
+		err = errBallots
+		return
+	}
+
+	return ballots.Judgments, ballots.Tallies, ballots.Proposals, ballots.Grades, nil
+}
+
+// ReadBallots reads the input YAML, along with the optional `weights` and `strata` of its judges
+func (r YamlReader) ReadBallots(input *io.Reader, worstGradeToBestGrade bool) (*Ballots, error) {
+	var document interface{}
+	errDecode := yaml.NewDecoder(*input).Decode(&document)
+	if errDecode != nil {
+		return nil, errors.New("Failed to read input YAML: " + errDecode.Error())
+	}
+
+	return readDocumentBallots(document, worstGradeToBestGrade)
+}